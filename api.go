@@ -111,6 +111,13 @@ type HashByte = i.HashByte
 // HashBytes represents a byte slice that should be hashed when redacted.
 type HashBytes = i.HashBytes
 
+// SecretValue is a marker interface to be implemented by types whose
+// values are secrets, such as credentials, which are not printed at
+// all by the formatters of this package: they are replaced by the
+// redacted marker ‹×›, or the placeholder printed by their SafeFormat
+// method. See Secret and Config.
+type SecretValue = i.SecretValue
+
 // RedactableString is a string that contains a mix of safe and unsafe
 // bits of data, but where it is known that unsafe bits are enclosed
 // by redaction markers ‹ and ›, and occurrences of the markers
@@ -128,6 +135,79 @@ type RedactableString = m.RedactableString
 // or the methods below.
 type RedactableBytes = m.RedactableBytes
 
+// MarkerError is returned by the Validate method of RedactableString
+// and RedactableBytes when the markers are malformed.
+type MarkerError = m.MarkerError
+
+// MarkerProblem is a malformed marker reported by MarkerError.
+type MarkerProblem = m.MarkerProblem
+
+// MarkerProblemKind is the kind of a MarkerProblem.
+type MarkerProblemKind = m.MarkerProblemKind
+
+const (
+	// UnclosedStartMarker is a start marker without a matching end
+	// marker, for example in a truncated string.
+	UnclosedStartMarker = m.UnclosedStartMarker
+	// UnmatchedEndMarker is an end marker outside of an unsafe region.
+	UnmatchedEndMarker = m.UnmatchedEndMarker
+	// NestedStartMarker is a start marker inside an unsafe region.
+	NestedStartMarker = m.NestedStartMarker
+)
+
+// Span is a safe or unsafe region of a redactable string. See the
+// Spans method of RedactableString and RedactableBytes.
+type Span = m.Span
+
+// SpanKind is the kind of a Span.
+type SpanKind = m.SpanKind
+
+const (
+	// SafeSpan is text outside of the redaction markers.
+	SafeSpan = m.SafeSpan
+	// UnsafeSpan is text enclosed in redaction markers.
+	UnsafeSpan = m.UnsafeSpan
+	// HashSpan is unsafe text marked for hashing.
+	HashSpan = m.HashSpan
+	// RedactedSpan is unsafe text that has already been redacted.
+	RedactedSpan = m.RedactedSpan
+)
+
+// FromSpans returns the redactable string made of spans, for example
+// as returned by the Spans method of RedactableString. It returns an
+// error if the text of a span contains markers.
+func FromSpans(spans []Span) (RedactableString, error) { return m.FromSpans(spans) }
+
+// StructuredRedactableString is a RedactableString that is marshaled
+// to JSON in a structured form, with the plain text and the table of
+// its unsafe spans:
+//
+//	{"text":"user alice","spans":[{"kind":"unsafe","start":5,"end":10}]}
+//
+// RedactableString and RedactableBytes are marshaled to a JSON string;
+// their MarshalStructuredJSON method produces the structured form for
+// a single value. The UnmarshalJSON methods of the three types accept
+// both forms.
+type StructuredRedactableString = m.StructuredRedactableString
+
+// Dialect is a representation of the redaction markers in a
+// redactable string. See UnicodeDialect and ASCIIDialect.
+type Dialect = m.Dialect
+
+// UnicodeDialect is the dialect of RedactableString and
+// RedactableBytes, using the markers ‹ and ›.
+var UnicodeDialect = m.UnicodeDialect
+
+// ASCIIDialect is a dialect using only ASCII characters, for the
+// systems that mangle or reject the markers of the Unicode dialect.
+// The unsafe regions are delimited by \[ and \], and literal
+// backslashes are doubled.
+var ASCIIDialect = m.ASCIIDialect
+
+// ConvertDialect converts s, a redactable string in the dialect from,
+// to the dialect to.
+func ConvertDialect(s []byte, from, to *Dialect) []byte { return m.ConvertDialect(s, from, to) }
+
 // StartMarker returns the start delimiter for an unsafe string.
 func StartMarker() []byte { return m.StartMarker() }
 
@@ -137,6 +217,29 @@ func EndMarker() []byte { return m.EndMarker() }
 // RedactedMarker returns the special string used by Redact.
 func RedactedMarker() []byte { return m.RedactedMarker() }
 
+// HashPrefixMarker returns the prefix that marks an unsafe string
+// for hashing instead of full redaction.
+func HashPrefixMarker() []byte { return m.HashPrefixMarker() }
+
+// EnableLosslessEscaping makes the escaping of the markers found
+// inside the data reversible. By default, they are replaced by ?, so
+// that StripMarkers cannot reproduce the original data. With lossless
+// escaping, the escaped bytes are encoded as ?{hex} instead, and
+// StripMarkersExact recovers the original data exactly. The redaction
+// of the unsafe data is unchanged.
+func EnableLosslessEscaping() { m.EnableLosslessEscaping() }
+
+// DisableLosslessEscaping restores the default escaping.
+func DisableLosslessEscaping() { m.DisableLosslessEscaping() }
+
+// IsLosslessEscapingEnabled returns true if lossless escaping is
+// enabled.
+func IsLosslessEscapingEnabled() bool { return m.IsLosslessEscapingEnabled() }
+
+// Unescape decodes the bytes escaped by lossless escaping in s. It
+// does not remove the redaction markers; see StripMarkersExact.
+func Unescape(s []byte) []byte { return m.Unescape(s) }
+
 // EscapeMarkers escapes the special delimiters from the provided
 // byte slice.
 func EscapeMarkers(s []byte) []byte { return m.EscapeMarkers(s) }
@@ -200,6 +303,15 @@ func RegisterSafeType(t reflect.Type) {
 	ifmt.RegisterSafeType(t)
 }
 
+// RegisterSafeFormatter registers a function to format the values of
+// a data type during the production of redactable strings, as if the
+// type implemented SafeFormatter. This is meant for the types defined
+// in other packages. See the stdsafe package for the types of the
+// standard library.
+func RegisterSafeFormatter(t reflect.Type, fn func(v interface{}, p i.SafePrinter, verb rune)) {
+	ifmt.RegisterSafeFormatter(t, fn)
+}
+
 // EnableHashing enables hash-based redaction with an optional salt.
 // Hash markers (‹†value›) will be replaced with hashes instead of being fully redacted.
 // When salt is nil, hash markers use plain SHA-256.
@@ -213,3 +325,9 @@ func EnableHashing(salt []byte) {
 func DisableHashing() {
 	m.DisableHashing()
 }
+
+// IsHashingEnabled reports whether hash-based redaction is currently
+// enabled via EnableHashing.
+func IsHashingEnabled() bool {
+	return m.IsHashingEnabled()
+}
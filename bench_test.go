@@ -58,3 +58,53 @@ func BenchmarkRedactCall_HashWithSalt(b *testing.B) {
 	}
 }
 
+// BenchmarkAppendf reuses the destination buffer across calls, so that
+// the formatting itself does not allocate.
+func BenchmarkAppendf(b *testing.B) {
+	var buf []byte
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = Appendf(buf[:0], "user=%s action=%s", "alice", SafeString("login"))
+	}
+}
+
+// benchLine is a typical log line for the in-place benchmarks below.
+var benchLine = Sprintf("user=%s action=%s ip=%s", "alice", Safe("login"), "10.0.0.1").ToBytes()
+
+// BenchmarkRedactBytes allocates a new buffer for every line.
+func BenchmarkRedactBytes(b *testing.B) {
+	buf := make([]byte, len(benchLine))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		copy(buf, benchLine)
+		_ = RedactableBytes(buf).Redact()
+	}
+}
+
+// BenchmarkRedactInPlace reuses the storage of the line.
+func BenchmarkRedactInPlace(b *testing.B) {
+	buf := make([]byte, len(benchLine))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		copy(buf, benchLine)
+		_ = RedactableBytes(buf).RedactInPlace()
+	}
+}
+
+func BenchmarkStripMarkersBytes(b *testing.B) {
+	buf := make([]byte, len(benchLine))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		copy(buf, benchLine)
+		_ = RedactableBytes(buf).StripMarkers()
+	}
+}
+
+func BenchmarkStripMarkersInPlace(b *testing.B) {
+	buf := make([]byte, len(benchLine))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		copy(buf, benchLine)
+		_ = RedactableBytes(buf).StripMarkersInPlace()
+	}
+}
@@ -0,0 +1,345 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package bundle
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// format is the kind of a bundle.
+type format int
+
+const (
+	formatDir format = iota
+	formatZip
+	formatTar
+	formatTarGz
+)
+
+// formatOf determines the bundle format from a file name.
+func formatOf(name string) format {
+	switch lower := strings.ToLower(name); {
+	case strings.HasSuffix(lower, ".zip"):
+		return formatZip
+	case strings.HasSuffix(lower, ".tar"):
+		return formatTar
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return formatTarGz
+	default:
+		return formatDir
+	}
+}
+
+// source is a bundle being read.
+type source interface {
+	// walk calls fn for each file in the bundle, stopping at the first
+	// error.
+	walk(fn func(file) error) error
+	close() error
+}
+
+func openSource(name string) (source, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return dirSource(name), nil
+	}
+	switch formatOf(name) {
+	case formatZip:
+		r, err := zip.OpenReader(name)
+		if err != nil {
+			return nil, err
+		}
+		return zipSource{r}, nil
+	case formatTar, formatTarGz:
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		return &tarSource{f: f, gzipped: formatOf(name) == formatTarGz}, nil
+	default:
+		return nil, fmt.Errorf("%s: unsupported bundle format", name)
+	}
+}
+
+// dirSource is a directory tree.
+type dirSource string
+
+func (d dirSource) walk(fn func(file) error) error {
+	root := string(d)
+	return filepath.WalkDir(root, func(p string, de fs.DirEntry, err error) error {
+		if err != nil || de.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		info, err := de.Info()
+		if err != nil {
+			return err
+		}
+		f := file{
+			name:    filepath.ToSlash(rel),
+			size:    info.Size(),
+			mode:    info.Mode(),
+			modTime: info.ModTime(),
+			open:    func() (io.ReadCloser, error) { return os.Open(p) },
+		}
+		if !info.Mode().IsRegular() {
+			f.reason = "not a regular file"
+		}
+		return fn(f)
+	})
+}
+
+func (d dirSource) close() error { return nil }
+
+// zipSource is a zip archive. The files in a zip archive can be read
+// concurrently.
+type zipSource struct {
+	r *zip.ReadCloser
+}
+
+func (z zipSource) walk(fn func(file) error) error {
+	for _, zf := range z.r.File {
+		zf := zf
+		info := zf.FileInfo()
+		if info.IsDir() {
+			continue
+		}
+		f := file{
+			name:    cleanArchiveName(zf.Name),
+			size:    info.Size(),
+			mode:    info.Mode(),
+			modTime: zf.Modified,
+			open:    zf.Open,
+		}
+		if !info.Mode().IsRegular() {
+			f.reason = "not a regular file"
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (z zipSource) close() error { return z.r.Close() }
+
+// tarSource is a tar archive, optionally compressed with gzip. The
+// files in a tar archive can only be read sequentially, so their
+// contents are loaded in memory as they are walked.
+type tarSource struct {
+	f       *os.File
+	gzipped bool
+}
+
+func (t *tarSource) walk(fn func(file) error) error {
+	var r io.Reader = t.f
+	if t.gzipped {
+		gz, err := gzip.NewReader(t.f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		info := hdr.FileInfo()
+		if info.IsDir() {
+			continue
+		}
+		f := file{
+			name:    cleanArchiveName(hdr.Name),
+			size:    hdr.Size,
+			mode:    info.Mode(),
+			modTime: hdr.ModTime,
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			f.reason = "not a regular file"
+		} else {
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return err
+			}
+			f.open = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(data)), nil
+			}
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+}
+
+func (t *tarSource) close() error { return t.f.Close() }
+
+// cleanArchiveName removes the leading "./" that some archivers add
+// to the names of the files.
+func cleanArchiveName(name string) string {
+	for strings.HasPrefix(name, "./") {
+		name = name[2:]
+	}
+	return name
+}
+
+// sink is a bundle being written.
+type sink interface {
+	// writeFile adds a file to the bundle, with the contents produced
+	// by fn. It can be called concurrently.
+	writeFile(name string, mode fs.FileMode, modTime time.Time, fn func(w io.Writer) error) error
+	close() error
+}
+
+func createSink(name string) (sink, error) {
+	if formatOf(name) == formatDir {
+		if err := os.Mkdir(name, 0755); err != nil {
+			return nil, err
+		}
+		return dirSink(name), nil
+	}
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return nil, err
+	}
+	s := &archiveSink{f: f}
+	switch formatOf(name) {
+	case formatZip:
+		s.zw = zip.NewWriter(f)
+	case formatTarGz:
+		s.gz = gzip.NewWriter(f)
+		s.tw = tar.NewWriter(s.gz)
+	default:
+		s.tw = tar.NewWriter(f)
+	}
+	return s, nil
+}
+
+// dirSink is a directory tree. Files can be written concurrently.
+type dirSink string
+
+func (d dirSink) writeFile(
+	name string, mode fs.FileMode, modTime time.Time, fn func(w io.Writer) error,
+) error {
+	p := filepath.Join(string(d), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_EXCL, filePerm(mode))
+	if err != nil {
+		return err
+	}
+	if err := fn(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Chtimes(p, modTime, modTime)
+}
+
+func (d dirSink) close() error { return nil }
+
+// archiveSink is a zip or tar archive. The archive writers are not
+// concurrent, so the file contents are produced in memory before they
+// are added to the archive.
+type archiveSink struct {
+	f  *os.File
+	zw *zip.Writer
+	gz *gzip.Writer
+	tw *tar.Writer
+
+	mu sync.Mutex
+}
+
+func (s *archiveSink) writeFile(
+	name string, mode fs.FileMode, modTime time.Time, fn func(w io.Writer) error,
+) error {
+	var buf bytes.Buffer
+	if err := fn(&buf); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.zw != nil {
+		hdr := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modTime}
+		hdr.SetMode(filePerm(mode))
+		w, err := s.zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(buf.Bytes())
+		return err
+	}
+	if err := s.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     int64(filePerm(mode)),
+		Size:     int64(buf.Len()),
+		ModTime:  modTime,
+	}); err != nil {
+		return err
+	}
+	_, err := s.tw.Write(buf.Bytes())
+	return err
+}
+
+func (s *archiveSink) close() (err error) {
+	if s.zw != nil {
+		err = s.zw.Close()
+	} else {
+		err = s.tw.Close()
+		if s.gz != nil {
+			if gzErr := s.gz.Close(); err == nil {
+				err = gzErr
+			}
+		}
+	}
+	if closeErr := s.f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// filePerm returns the permission bits for an output file.
+func filePerm(mode fs.FileMode) fs.FileMode {
+	if perm := mode.Perm(); perm != 0 {
+		return perm
+	}
+	return 0644
+}
@@ -0,0 +1,433 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package bundle redacts collections of files, such as support
+// bundles, before they are shared.
+//
+// A bundle is either a directory tree, or a zip or tar archive. Each
+// file in the bundle is either redacted, copied as-is or excluded from
+// the output, according to a Policy. A manifest recording what was
+// done to each file is added to the output, so that the owner of the
+// data can audit what is being shared.
+package bundle
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/redact"
+)
+
+// Action is what to do with a file in the bundle.
+type Action int
+
+const (
+	// Exclude omits the file from the output. This is the zero value,
+	// so that files not covered by a policy are not shared by
+	// accident.
+	Exclude Action = iota
+	// Copy copies the file to the output without modification.
+	Copy
+	// Redact copies the file to the output with its contents
+	// redacted, as per RedactableBytes.Redact after the malformed
+	// markers are repaired by RedactableBytes.Repair.
+	Redact
+)
+
+// Policy determines the action taken on each file of a bundle.
+//
+// The patterns use the syntax of path.Match and are matched against
+// the slash-separated path of the file relative to the root of the
+// bundle. A pattern that does not contain a slash is also matched
+// against the base name of the file, so that "*.log" matches log files
+// in any directory.
+type Policy struct {
+	// Exclude lists the patterns of the files to exclude. They take
+	// precedence over the other patterns.
+	Exclude []string
+	// Redact lists the patterns of the files to redact.
+	Redact []string
+	// Copy lists the patterns of the files to copy without redaction.
+	Copy []string
+	// Default is the action for the files that match no pattern.
+	Default Action
+	// Parallelism is the number of files processed concurrently.
+	// Defaults to GOMAXPROCS.
+	Parallelism int
+}
+
+// Validate checks the policy for malformed patterns.
+func (p *Policy) Validate() error {
+	for _, patterns := range [][]string{p.Exclude, p.Redact, p.Copy} {
+		for _, pat := range patterns {
+			if _, err := path.Match(pat, ""); err != nil {
+				return fmt.Errorf("invalid pattern %q: %w", pat, err)
+			}
+		}
+	}
+	return nil
+}
+
+// actionFor returns the action for the file at the given path.
+func (p *Policy) actionFor(name string) Action {
+	switch {
+	case matchAny(p.Exclude, name):
+		return Exclude
+	case matchAny(p.Redact, name):
+		return Redact
+	case matchAny(p.Copy, name):
+		return Copy
+	default:
+		return p.Default
+	}
+}
+
+func matchAny(patterns []string, name string) bool {
+	base := path.Base(name)
+	for _, pat := range patterns {
+		if ok, _ := path.Match(pat, name); ok {
+			return true
+		}
+		if !strings.Contains(pat, "/") {
+			if ok, _ := path.Match(pat, base); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Status records what was done to a file.
+type Status string
+
+const (
+	// StatusRedacted indicates that the file was redacted.
+	StatusRedacted Status = "redacted"
+	// StatusSkipped indicates that the file was copied without
+	// redaction.
+	StatusSkipped Status = "skipped"
+	// StatusExcluded indicates that the file was not included in the
+	// output.
+	StatusExcluded Status = "excluded"
+)
+
+// ManifestName is the name of the manifest file added at the root of
+// the output.
+const ManifestName = "redaction-manifest.json"
+
+// Manifest records what was done to each file of a bundle.
+type Manifest struct {
+	// Files lists the files of the input, sorted by path.
+	Files []FileEntry `json:"files"`
+	// Counts summarizes the files.
+	Counts Counts `json:"counts"`
+}
+
+// FileEntry describes one file of the bundle in the manifest.
+type FileEntry struct {
+	// Path is the slash-separated path of the file relative to the
+	// root of the bundle.
+	Path   string `json:"path"`
+	Status Status `json:"status"`
+	// Size is the size of the file in the input.
+	Size int64 `json:"size"`
+	// OutputSize is the size of the file in the output.
+	OutputSize int64 `json:"output_size"`
+	// Regions is the number of unsafe regions redacted in the file.
+	Regions int `json:"regions,omitempty"`
+	// Reason explains why a file was excluded when it is not
+	// because of the policy, e.g. because its path is unsafe.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Counts summarizes a manifest.
+type Counts struct {
+	Redacted int `json:"redacted"`
+	Skipped  int `json:"skipped"`
+	Excluded int `json:"excluded"`
+	// Regions is the total number of unsafe regions redacted.
+	Regions int `json:"regions"`
+}
+
+// RedactBundle processes the bundle at src according to the policy and
+// writes the result to dst. Each of src and dst may be a directory, or
+// a zip (.zip) or tar (.tar, .tar.gz, .tgz) archive; the output kind
+// is determined by the name of dst, and dst must not exist already.
+//
+// The manifest is both returned and written to the output under
+// ManifestName. If an error occurs, the output is incomplete and must
+// be discarded.
+func RedactBundle(ctx context.Context, src, dst string, policy Policy) (*Manifest, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	in, err := openSource(src)
+	if err != nil {
+		return nil, err
+	}
+	defer in.close()
+	out, err := createSink(dst)
+	if err != nil {
+		return nil, err
+	}
+	m, err := process(ctx, in, out, &policy)
+	if err == nil {
+		err = writeManifest(out, m)
+	}
+	if closeErr := out.close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// process runs the policy over the files of in, writing the results
+// to out.
+func process(ctx context.Context, in source, out sink, policy *Policy) (*Manifest, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	parallelism := policy.Parallelism
+	if parallelism <= 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+
+	var (
+		mu       sync.Mutex
+		entries  []FileEntry
+		firstErr error
+	)
+	setErr := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	work := make(chan file)
+	var wg sync.WaitGroup
+	for i := 0; i < parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range work {
+				e, err := processFile(f, out, policy)
+				if err != nil {
+					setErr(fmt.Errorf("%s: %w", f.name, err))
+					continue
+				}
+				mu.Lock()
+				entries = append(entries, e)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// seen are the paths already walked. The later files with the same
+	// path are excluded, so that the manifest lists each path once as
+	// included.
+	seen := map[string]bool{}
+	walkErr := in.walk(func(f file) error {
+		if seen[f.name] && f.reason == "" {
+			f.reason = "duplicate path"
+		}
+		seen[f.name] = true
+		select {
+		case work <- f:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	close(work)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if walkErr != nil {
+		return nil, walkErr
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Path != entries[j].Path {
+			return entries[i].Path < entries[j].Path
+		}
+		// The duplicates follow the file written to the output.
+		return entries[i].Reason == "" && entries[j].Reason != ""
+	})
+	m := &Manifest{Files: entries}
+	for _, e := range entries {
+		switch e.Status {
+		case StatusRedacted:
+			m.Counts.Redacted++
+		case StatusSkipped:
+			m.Counts.Skipped++
+		case StatusExcluded:
+			m.Counts.Excluded++
+		}
+		m.Counts.Regions += e.Regions
+	}
+	return m, nil
+}
+
+// processFile applies the policy to one file.
+func processFile(f file, out sink, policy *Policy) (FileEntry, error) {
+	e := FileEntry{Path: f.name, Size: f.size, Status: StatusExcluded}
+	if f.reason != "" {
+		e.Reason = f.reason
+		return e, nil
+	}
+	if !isSafePath(f.name) {
+		e.Reason = "unsafe path"
+		return e, nil
+	}
+	if f.name == ManifestName {
+		e.Reason = "reserved name"
+		return e, nil
+	}
+	action := policy.actionFor(f.name)
+	if action == Exclude {
+		return e, nil
+	}
+	r, err := f.open()
+	if err != nil {
+		return e, err
+	}
+	defer r.Close()
+
+	err = out.writeFile(f.name, f.mode, f.modTime, func(w io.Writer) error {
+		cw := &countingWriter{w: w}
+		if action == Copy {
+			e.Status = StatusSkipped
+			_, err := io.Copy(cw, r)
+			e.OutputSize = cw.n
+			return err
+		}
+		e.Status = StatusRedacted
+		var err error
+		e.Regions, err = redactStream(cw, r)
+		e.OutputSize = cw.n
+		return err
+	})
+	return e, err
+}
+
+// isSafePath returns true if the name is a relative path that stays
+// within the root of the bundle. Backslashes are rejected, as they are
+// path separators on Windows.
+func isSafePath(name string) bool {
+	return name != "" && name != "." && !strings.HasPrefix(name, "/") &&
+		!strings.Contains(name, `\`) &&
+		path.Clean(name) == name && name != ".." && !strings.HasPrefix(name, "../")
+}
+
+// redactStream copies r to w, redacting its contents. Redaction
+// markers never span multiple lines, so the input is processed line by
+// line. The malformed markers of each line are repaired first, so that
+// the text following an unclosed start marker, e.g. in a log file
+// truncated by rotation, is redacted too. It returns the number of
+// unsafe regions redacted.
+func redactStream(w io.Writer, r io.Reader) (regions int, err error) {
+	start, end := redact.StartMarker(), redact.EndMarker()
+	br := bufio.NewReaderSize(r, 64<<10)
+	bw := bufio.NewWriterSize(w, 64<<10)
+	for {
+		line, readErr := br.ReadBytes('\n')
+		if len(line) > 0 {
+			line = repairLine(line)
+			for rest := line; ; {
+				i := bytes.Index(rest, start)
+				if i == -1 {
+					break
+				}
+				rest = rest[i+len(start):]
+				j := bytes.Index(rest, end)
+				if j == -1 {
+					break
+				}
+				regions++
+				rest = rest[j+len(end):]
+			}
+			if _, err := bw.Write(redact.RedactableBytes(line).Redact()); err != nil {
+				return regions, err
+			}
+		}
+		if readErr == io.EOF {
+			return regions, bw.Flush()
+		}
+		if readErr != nil {
+			return regions, readErr
+		}
+	}
+}
+
+// repairLine repairs the markers of a line, keeping the newline out of
+// the unsafe region added for an unclosed start marker.
+func repairLine(line []byte) []byte {
+	body := bytes.TrimSuffix(line, []byte("\n"))
+	repaired := redact.RedactableBytes(body).Repair()
+	if len(body) < len(line) {
+		repaired = append(repaired, '\n')
+	}
+	return repaired
+}
+
+func writeManifest(out sink, m *Manifest) error {
+	return out.writeFile(ManifestName, 0644, time.Now(), func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(m)
+	})
+}
+
+// file is a regular file in a source bundle.
+type file struct {
+	// name is the slash-separated path relative to the root.
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+	// reason, if set, indicates that the file cannot be processed
+	// and must be excluded.
+	reason string
+	// open returns the contents of the file. It may be called from a
+	// different goroutine than the one that produced the file.
+	open func() (io.ReadCloser, error)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cockroachdb/redact"
+	"github.com/cockroachdb/redact/bundle"
+)
+
+// patternList is a flag that accumulates comma-separated patterns.
+type patternList []string
+
+func (p *patternList) String() string { return strings.Join(*p, ",") }
+
+func (p *patternList) Set(s string) error {
+	*p = append(*p, strings.Split(s, ",")...)
+	return nil
+}
+
+var bundleFlags struct {
+	policy       bundle.Policy
+	defaultCopy  bool
+	hashSaltFile string
+}
+
+var bundleCmd = &command{
+	name:  "bundle",
+	short: "redact a directory or zip/tar archive into a new one",
+	setFlags: func(fs *flag.FlagSet) {
+		bundleFlags.policy = bundle.Policy{}
+		fs.Var((*patternList)(&bundleFlags.policy.Redact), "redact",
+			"comma-separated `patterns` of the files to redact (repeatable)")
+		fs.Var((*patternList)(&bundleFlags.policy.Copy), "copy",
+			"comma-separated `patterns` of the files to copy as-is (repeatable)")
+		fs.Var((*patternList)(&bundleFlags.policy.Exclude), "exclude",
+			"comma-separated `patterns` of the files to exclude (repeatable)")
+		fs.BoolVar(&bundleFlags.defaultCopy, "copy-others", false,
+			"copy the files that match no pattern instead of excluding them")
+		fs.IntVar(&bundleFlags.policy.Parallelism, "parallel", 0,
+			"number of files processed concurrently (default GOMAXPROCS)")
+		fs.StringVar(&bundleFlags.hashSaltFile, "hash-salt-file", "",
+			"enable hashing of the hash-marked values, using the salt read from `file`\n"+
+				"(trailing newlines are ignored)")
+	},
+	run: func(e *env, args []string) error {
+		if len(args) != 2 {
+			return errors.New("expected source and destination arguments")
+		}
+		policy := bundleFlags.policy
+		if bundleFlags.defaultCopy {
+			policy.Default = bundle.Copy
+		}
+		if bundleFlags.hashSaltFile != "" {
+			salt, err := os.ReadFile(bundleFlags.hashSaltFile)
+			if err != nil {
+				return err
+			}
+			redact.EnableHashing(bytes.TrimRight(salt, "\r\n"))
+			defer redact.DisableHashing()
+		}
+		m, err := bundle.RedactBundle(context.Background(), args[0], args[1], policy)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(e.stdout, "%d redacted (%d regions), %d skipped, %d excluded; manifest: %s\n",
+			m.Counts.Redacted, m.Counts.Regions, m.Counts.Skipped, m.Counts.Excluded, bundle.ManifestName)
+		return nil
+	},
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cockroachdb/redact"
+)
+
+var checkCmd = &command{
+	name:  "check",
+	short: "report the malformed marker regions",
+	run: func(e *env, args []string) error {
+		failed := false
+		if err := forEachInput(e, args, func(name string, r io.Reader) error {
+			return forEachLine(r, func(lineNum int, line []byte) error {
+				var me *redact.MarkerError
+				if !errors.As(redact.RedactableBytes(line).Validate(), &me) {
+					return nil
+				}
+				failed = true
+				for _, p := range me.Problems {
+					fmt.Fprintf(e.stdout, "%s:%d:%d: %s\n", name, lineNum, p.Pos+1, p.Kind)
+				}
+				return nil
+			})
+		}); err != nil {
+			return err
+		}
+		if failed {
+			return errSilent
+		}
+		return nil
+	},
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Command redact processes files containing redactable strings, for
+// example log files produced using the redact package.
+//
+// Usage:
+//
+//	redact <command> [flags] [file...]
+//
+// The commands are:
+//
+//	redact   redact the unsafe data and print the result
+//	strip    remove the redaction markers and print the result
+//	check    report the malformed marker regions
+//	stats    report the number of unsafe regions and bytes per file
+//	show     display the input with the unsafe regions highlighted
+//	bundle   redact a directory or zip/tar archive into a new one
+//
+// Except for bundle, the commands read the files named on the command
+// line, or the standard input if there are none or the file name is
+// "-".
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// command is a subcommand of the tool.
+type command struct {
+	name  string
+	short string
+	// setFlags, if set, defines the command-specific flags.
+	setFlags func(fs *flag.FlagSet)
+	// run executes the command on the named inputs, or the standard
+	// input if there are none.
+	run func(env *env, args []string) error
+}
+
+// env is the environment of a command.
+type env struct {
+	stdin          io.Reader
+	stdout, stderr io.Writer
+}
+
+var commands = []*command{redactCmd, stripCmd, checkCmd, statsCmd, showCmd, bundleCmd}
+
+// errSilent is returned by commands that have already reported their
+// failure and must exit with a non-zero status.
+var errSilent = errors.New("command failed")
+
+func main() {
+	os.Exit(run(os.Args[1:], &env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}))
+}
+
+// run executes the command line and returns the process exit status.
+func run(args []string, e *env) int {
+	if len(args) == 0 {
+		usage(e.stderr)
+		return 2
+	}
+	var cmd *command
+	for _, c := range commands {
+		if c.name == args[0] {
+			cmd = c
+		}
+	}
+	if cmd == nil {
+		if args[0] != "help" && args[0] != "-h" && args[0] != "--help" {
+			fmt.Fprintf(e.stderr, "redact: unknown command %q\n", args[0])
+		}
+		usage(e.stderr)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("redact "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	if cmd.setFlags != nil {
+		cmd.setFlags(fs)
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if err := cmd.run(e, fs.Args()); err != nil {
+		if err != errSilent {
+			fmt.Fprintf(e.stderr, "redact %s: %v\n", cmd.name, err)
+		}
+		return 1
+	}
+	return 0
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "usage: redact <command> [flags] [file...]\n\ncommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-8s %s\n", c.name, c.short)
+	}
+	fmt.Fprintf(w, "\nRun 'redact <command> -h' for the command flags.\n")
+}
+
+// forEachInput calls fn for each input named in args, or for the
+// standard input if args is empty.
+func forEachInput(e *env, args []string, fn func(name string, r io.Reader) error) error {
+	if len(args) == 0 {
+		args = []string{"-"}
+	}
+	for _, name := range args {
+		if name == "-" {
+			if err := fn("<stdin>", e.stdin); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := func() error {
+			f, err := os.Open(name)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			return fn(name, f)
+		}(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forEachLine calls fn for each line in r, including its terminating
+// newline if any. Redaction markers never span multiple lines, so the
+// lines can be processed independently.
+func forEachLine(r io.Reader, fn func(lineNum int, line []byte) error) error {
+	br := bufio.NewReaderSize(r, 64<<10)
+	for lineNum := 1; ; lineNum++ {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			if err := fn(lineNum, line); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
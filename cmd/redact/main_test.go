@@ -0,0 +1,179 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/redact"
+)
+
+func runTool(t *testing.T, stdin string, args ...string) (status int, stdout, stderr string) {
+	t.Helper()
+	var out, errOut strings.Builder
+	status = run(args, &env{stdin: strings.NewReader(stdin), stdout: &out, stderr: &errOut})
+	return status, out.String(), errOut.String()
+}
+
+func TestRedactStrip(t *testing.T) {
+	input := string(redact.Sprintf("hello %s\nsafe line\nuser %s", "world", redact.HashString("alice")))
+
+	status, out, _ := runTool(t, input, "redact")
+	if exp := "hello ‹×›\nsafe line\nuser ‹×›"; status != 0 || out != exp {
+		t.Errorf("redact: expected %q, got %d %q", exp, status, out)
+	}
+
+	status, out, _ = runTool(t, input, "strip")
+	if exp := "hello world\nsafe line\nuser alice"; status != 0 || out != exp {
+		t.Errorf("strip: expected %q, got %d %q", exp, status, out)
+	}
+
+	saltFile := filepath.Join(t.TempDir(), "salt")
+	if err := os.WriteFile(saltFile, []byte("my-salt\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	redact.EnableHashing([]byte("my-salt"))
+	exp := string(redact.RedactableString(input).Redact())
+	redact.DisableHashing()
+	status, out, _ = runTool(t, input, "redact", "--hash-salt-file", saltFile)
+	if status != 0 || out != exp {
+		t.Errorf("redact with salt: expected %q, got %d %q", exp, status, out)
+	}
+	if redact.IsHashingEnabled() {
+		t.Errorf("expected hashing to be disabled after the command")
+	}
+
+	truncated := "lice› logged in\nuser ‹bob\n"
+	status, out, _ = runTool(t, truncated, "redact")
+	if exp := "‹×› logged in\nuser ‹×›\n"; status != 0 || out != exp {
+		t.Errorf("redact: expected %q, got %d %q", exp, status, out)
+	}
+	status, out, _ = runTool(t, truncated, "redact", "--repair=false")
+	if exp := "lice› logged in\nuser ‹bob\n"; status != 0 || out != exp {
+		t.Errorf("redact without repair: expected %q, got %d %q", exp, status, out)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	status, out, _ := runTool(t, "a ‹b› c\n‹d›\n", "check")
+	if status != 0 || out != "" {
+		t.Errorf("expected success, got %d %q", status, out)
+	}
+
+	input := "ok ‹fine›\n‹open\nstray› ‹x ‹y›\n"
+	status, out, _ = runTool(t, input, "check")
+	exp := `<stdin>:2:1: start marker without end marker
+<stdin>:3:6: end marker without start marker
+<stdin>:3:15: start marker inside unsafe region
+`
+	if status != 1 || out != exp {
+		t.Errorf("expected:\n%s\ngot %d:\n%s", exp, status, out)
+	}
+}
+
+func TestStats(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.log")
+	b := filepath.Join(dir, "b.log")
+	if err := os.WriteFile(a, []byte("x ‹abc› ‹†de›\ny\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte("plain\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	status, out, _ := runTool(t, "", "stats", a, b)
+	exp := `lines  bytes  regions  hashed  unsafe bytes  file
+2      26     2        1       5             ` + a + `
+1      6      0        0       0             ` + b + `
+3      32     2        1       5             total
+`
+	if status != 0 || out != exp {
+		t.Errorf("expected:\n%s\ngot %d:\n%s", exp, status, out)
+	}
+}
+
+func TestBundle(t *testing.T) {
+	tmp := t.TempDir()
+	src := filepath.Join(tmp, "src")
+	if err := os.MkdirAll(filepath.Join(src, "logs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, contents := range map[string]string{
+		"logs/a.log": "user ‹alice›\n",
+		"notes.txt":  "‹kept›",
+		"core.dump":  "secret",
+	} {
+		if err := os.WriteFile(filepath.Join(src, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dst := filepath.Join(tmp, "out")
+	status, out, errOut := runTool(t, "", "bundle",
+		"--redact", "*.log", "--exclude", "*.dump", "--copy-others", src, dst)
+	if exp := "1 redacted (1 regions), 1 skipped, 1 excluded; manifest: redaction-manifest.json\n"; status != 0 || out != exp {
+		t.Errorf("expected %q, got %d %q %q", exp, status, out, errOut)
+	}
+	for name, exp := range map[string]string{
+		"logs/a.log": "user ‹×›\n",
+		"notes.txt":  "‹kept›",
+	} {
+		if data, err := os.ReadFile(filepath.Join(dst, name)); err != nil || string(data) != exp {
+			t.Errorf("%s: expected %q, got %q (%v)", name, exp, data, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dst, "core.dump")); !os.IsNotExist(err) {
+		t.Errorf("expected core.dump to be excluded, got %v", err)
+	}
+
+	status, _, errOut = runTool(t, "", "bundle", src)
+	if exp := "redact bundle: expected source and destination arguments\n"; status != 1 || errOut != exp {
+		t.Errorf("expected %q, got %d %q", exp, status, errOut)
+	}
+}
+
+func TestShow(t *testing.T) {
+	input := "user ‹<alice>›\nok\n"
+
+	status, out, _ := runTool(t, input, "show")
+	if exp := "user \x1b[1;31m<alice>\x1b[0m\nok\n"; status != 0 || out != exp {
+		t.Errorf("show: expected %q, got %d %q", exp, status, out)
+	}
+	status, out, _ = runTool(t, input, "show", "--redacted")
+	if exp := "user \x1b[2m×\x1b[0m\nok\n"; status != 0 || out != exp {
+		t.Errorf("show --redacted: expected %q, got %d %q", exp, status, out)
+	}
+	status, out, _ = runTool(t, input, "show", "--html")
+	exp := `<tr><td>&lt;stdin&gt;</td><td>1</td>` +
+		`<td><pre>user <span class="redact-unsafe">&lt;alice&gt;</span></pre></td>` +
+		`<td><pre>user <span class="redact-redacted">×</span></pre></td></tr>`
+	if status != 0 || !strings.Contains(out, exp) || !strings.HasSuffix(out, "</html>\n") {
+		t.Errorf("show --html: unexpected output %d %q", status, out)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	status, _, errOut := runTool(t, "", "frobnicate")
+	if status != 2 || !strings.Contains(errOut, `unknown command "frobnicate"`) {
+		t.Errorf("unexpected result: %d %q", status, errOut)
+	}
+	status, _, errOut = runTool(t, "", "strip", "/nonexistent")
+	if status != 1 || !strings.Contains(errOut, "redact strip: open /nonexistent") {
+		t.Errorf("unexpected result: %d %q", status, errOut)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"html"
+	"io"
+
+	"github.com/cockroachdb/redact"
+	"github.com/cockroachdb/redact/render"
+)
+
+var (
+	showHTML     bool
+	showRedacted bool
+)
+
+var showCmd = &command{
+	name:  "show",
+	short: "display the input with the unsafe regions highlighted",
+	setFlags: func(fs *flag.FlagSet) {
+		fs.BoolVar(&showHTML, "html", false,
+			"print an HTML page showing each line next to its redacted form")
+		fs.BoolVar(&showRedacted, "redacted", false,
+			"highlight the redacted form of the input instead of the input")
+	},
+	run: func(e *env, args []string) error {
+		if showHTML {
+			return showPage(e, args)
+		}
+		return transform(e, args, func(line []byte) []byte {
+			s := redact.RedactableBytes(line)
+			if showRedacted {
+				s = s.Redact()
+			}
+			return []byte(render.ANSI(s.ToString()))
+		})
+	},
+}
+
+// showPage prints an HTML page with a table of the input lines and
+// their redacted form.
+func showPage(e *env, args []string) error {
+	w := bufio.NewWriter(e.stdout)
+	fmt.Fprintf(w, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>redact</title>\n"+
+		"<style>\n%stable { border-collapse: collapse; }\ntd { border: 1px solid #ccc; vertical-align: top; }\n</style>\n"+
+		"</head>\n<body>\n<table>\n<tr><th>input</th><th>line</th><th>content</th><th>redacted</th></tr>\n",
+		render.DefaultCSS)
+	if err := forEachInput(e, args, func(name string, r io.Reader) error {
+		return forEachLine(r, func(lineNum int, line []byte) error {
+			s := redact.RedactableBytes(bytes.TrimSuffix(line, []byte("\n")))
+			_, err := fmt.Fprintf(w, "<tr><td>%s</td><td>%d</td><td><pre>%s</pre></td><td><pre>%s</pre></td></tr>\n",
+				html.EscapeString(name), lineNum, render.HTML(s.ToString()), render.HTML(s.Redact().ToString()))
+			return err
+		})
+	}); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "</table>\n</body>\n</html>\n")
+	return w.Flush()
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/cockroachdb/redact"
+)
+
+var statsCmd = &command{
+	name:  "stats",
+	short: "report the number of unsafe regions and bytes per file",
+	run: func(e *env, args []string) error {
+		tw := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "lines\tbytes\tregions\thashed\tunsafe bytes\tfile")
+		var total fileStats
+		n := 0
+		if err := forEachInput(e, args, func(name string, r io.Reader) error {
+			var st fileStats
+			if err := forEachLine(r, func(_ int, line []byte) error {
+				st.addLine(line)
+				return nil
+			}); err != nil {
+				return err
+			}
+			st.print(tw, name)
+			total.add(st)
+			n++
+			return nil
+		}); err != nil {
+			return err
+		}
+		if n > 1 {
+			total.print(tw, "total")
+		}
+		return tw.Flush()
+	},
+}
+
+// fileStats summarizes the redactable contents of a file.
+type fileStats struct {
+	lines, bytes int
+	// regions is the number of unsafe regions, including the hashed
+	// ones.
+	regions int
+	// hashed is the number of regions marked for hashing.
+	hashed int
+	// unsafeBytes is the number of bytes enclosed in unsafe regions,
+	// excluding the markers themselves.
+	unsafeBytes int
+}
+
+func (st *fileStats) addLine(line []byte) {
+	start, end, hashPrefix := redact.StartMarker(), redact.EndMarker(), redact.HashPrefixMarker()
+	st.lines++
+	st.bytes += len(line)
+	for {
+		i := bytes.Index(line, start)
+		if i == -1 {
+			return
+		}
+		line = line[i+len(start):]
+		j := bytes.Index(line, end)
+		if j == -1 {
+			// Unclosed marker, reported by the check command.
+			return
+		}
+		content := line[:j]
+		st.regions++
+		if bytes.HasPrefix(content, hashPrefix) {
+			st.hashed++
+			content = content[len(hashPrefix):]
+		}
+		st.unsafeBytes += len(content)
+		line = line[j+len(end):]
+	}
+}
+
+func (st *fileStats) add(other fileStats) {
+	st.lines += other.lines
+	st.bytes += other.bytes
+	st.regions += other.regions
+	st.hashed += other.hashed
+	st.unsafeBytes += other.unsafeBytes
+}
+
+func (st *fileStats) print(w io.Writer, name string) {
+	fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%d\t%s\n",
+		st.lines, st.bytes, st.regions, st.hashed, st.unsafeBytes, name)
+}
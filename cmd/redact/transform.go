@@ -0,0 +1,92 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/cockroachdb/redact"
+)
+
+var (
+	hashSaltFile string
+	repair       bool
+)
+
+var redactCmd = &command{
+	name:  "redact",
+	short: "redact the unsafe data and print the result",
+	setFlags: func(fs *flag.FlagSet) {
+		fs.StringVar(&hashSaltFile, "hash-salt-file", "",
+			"enable hashing of the hash-marked values, using the salt read from `file`\n"+
+				"(trailing newlines are ignored)")
+		fs.BoolVar(&repair, "repair", true,
+			"repair the malformed markers before redaction, treating the text\n"+
+				"around them as unsafe; with --repair=false, the text following an\n"+
+				"unclosed start marker is printed as-is")
+	},
+	run: func(e *env, args []string) error {
+		if hashSaltFile != "" {
+			salt, err := os.ReadFile(hashSaltFile)
+			if err != nil {
+				return err
+			}
+			redact.EnableHashing(bytes.TrimRight(salt, "\r\n"))
+			defer redact.DisableHashing()
+		}
+		return transform(e, args, func(line []byte) []byte {
+			if repair {
+				// Keep the newline out of the unsafe region added
+				// for an unclosed start marker.
+				body := bytes.TrimSuffix(line, []byte("\n"))
+				repaired := redact.RedactableBytes(body).Repair()
+				if len(body) < len(line) {
+					repaired = append(repaired[:len(repaired):len(repaired)], '\n')
+				}
+				return repaired.Redact()
+			}
+			return redact.RedactableBytes(line).Redact()
+		})
+	},
+}
+
+var stripCmd = &command{
+	name:  "strip",
+	short: "remove the redaction markers and print the result",
+	run: func(e *env, args []string) error {
+		return transform(e, args, func(line []byte) []byte {
+			return redact.RedactableBytes(line).StripMarkers()
+		})
+	},
+}
+
+// transform applies fn to every line of the inputs and prints the
+// results to the standard output.
+func transform(e *env, args []string, fn func(line []byte) []byte) error {
+	w := bufio.NewWriter(e.stdout)
+	if err := forEachInput(e, args, func(_ string, r io.Reader) error {
+		return forEachLine(r, func(_ int, line []byte) error {
+			_, err := w.Write(fn(line))
+			return err
+		})
+	}); err != nil {
+		return err
+	}
+	return w.Flush()
+}
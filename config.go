@@ -0,0 +1,261 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package redact
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/cockroachdb/redact/internal/rfmt/fmtsort"
+)
+
+// Config returns a SafeFormatter rendering v, typically a
+// configuration struct, like the %+v verb does, but with the structure,
+// the field names and the map keys safe, and the values unsafe.
+//
+// The secret values are not printed at all: they are replaced by the
+// redacted marker ‹×›, so that they do not appear even in the
+// unredacted output. A value is secret when:
+//
+//   - its type implements SecretValue;
+//   - it is a struct field tagged `redact:"secret"`;
+//   - its field name or map key names a secret, e.g. Password,
+//     APIToken or private_key. The name is split into words at the
+//     separators and the case changes, and its last words must name
+//     the secret: MaxTokens and KeySpan do not name secrets.
+//
+// A struct field tagged `redact:"safe"` is printed safely, except for
+// the secrets it contains.
+//
+// The values that format themselves, i.e. implement SafeFormatter,
+// SafeValue, fmt.Stringer or error, are printed as by Print.
+func Config(v interface{}) SafeFormatter { return configFormatter{v} }
+
+type configFormatter struct{ v interface{} }
+
+// SafeFormat implements SafeFormatter.
+func (c configFormatter) SafeFormat(p SafePrinter, _ rune) {
+	w := configWalker{p: p, visiting: map[uintptr]bool{}}
+	w.value(reflect.ValueOf(c.v), false)
+}
+
+// secretNames are the last words of the names of the secret fields
+// and map keys, lowercased and separated by spaces.
+var secretNames = []string{
+	"access key",
+	"accesskey",
+	"api key",
+	"apikey",
+	"credential",
+	"credentials",
+	"passphrase",
+	"passwd",
+	"password",
+	"private key",
+	"privatekey",
+	"secret",
+	"secret key",
+	"signing key",
+	"signingkey",
+	"token",
+}
+
+// isSecretName returns true if the field name or map key names a
+// secret.
+func isSecretName(name string) bool {
+	words := nameWords(name)
+	for _, s := range secretNames {
+		if words == s || strings.HasSuffix(words, " "+s) {
+			return true
+		}
+	}
+	return false
+}
+
+// nameWords splits the name into lowercase words separated by spaces,
+// at the separators and at the case changes, e.g. APIToken becomes
+// "api token" and db_passwd "db passwd".
+func nameWords(name string) string {
+	var b strings.Builder
+	sep := func() {
+		if b.Len() > 0 && !strings.HasSuffix(b.String(), " ") {
+			b.WriteByte(' ')
+		}
+	}
+	rs := []rune(name)
+	for i, r := range rs {
+		switch {
+		case r == '_' || r == '-' || r == '.' || r == ' ':
+			sep()
+			continue
+		case i > 0 && unicode.IsUpper(r) &&
+			(!unicode.IsUpper(rs[i-1]) || i+1 < len(rs) && unicode.IsLower(rs[i+1])):
+			// The start of a word, e.g. the T of apiToken or APIToken.
+			sep()
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return strings.TrimSuffix(b.String(), " ")
+}
+
+var (
+	secretValueType   = reflect.TypeOf((*SecretValue)(nil)).Elem()
+	safeFormatterType = reflect.TypeOf((*SafeFormatter)(nil)).Elem()
+	safeValueType     = reflect.TypeOf((*SafeValue)(nil)).Elem()
+	stringerType      = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+	errorType         = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// configWalker prints the values for Config.
+type configWalker struct {
+	p SafePrinter
+	// visiting are the pointers and maps being printed, to break
+	// cycles.
+	visiting map[uintptr]bool
+}
+
+// value prints v, safely if safe is set.
+func (w *configWalker) value(v reflect.Value, safe bool) {
+	if !v.IsValid() {
+		w.p.SafeString("<nil>")
+		return
+	}
+	t := v.Type()
+	if t.Implements(secretValueType) {
+		if v.CanInterface() {
+			// Print the placeholder of the secret, e.g. a fingerprinted
+			// Secret.
+			w.p.Print(v.Interface())
+		} else {
+			w.secret()
+		}
+		return
+	}
+	if t.Implements(safeFormatterType) || t.Implements(safeValueType) ||
+		t.Implements(stringerType) || t.Implements(errorType) {
+		w.leaf(v, safe)
+		return
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			w.p.SafeString("<nil>")
+			return
+		}
+		if !w.enter(v) {
+			return
+		}
+		defer w.leave(v)
+		w.p.SafeRune('&')
+		w.value(v.Elem(), safe)
+
+	case reflect.Interface:
+		if v.IsNil() {
+			w.p.SafeString("<nil>")
+			return
+		}
+		w.value(v.Elem(), safe)
+
+	case reflect.Struct:
+		w.p.SafeRune('{')
+		for i := 0; i < v.NumField(); i++ {
+			if i > 0 {
+				w.p.SafeRune(' ')
+			}
+			f := t.Field(i)
+			w.p.SafeString(SafeString(f.Name))
+			w.p.SafeRune(':')
+			tag, _, _ := strings.Cut(f.Tag.Get("redact"), ",")
+			switch {
+			case tag == "secret" || isSecretName(f.Name):
+				w.secret()
+			default:
+				w.value(v.Field(i), safe || tag == "safe")
+			}
+		}
+		w.p.SafeRune('}')
+
+	case reflect.Map:
+		if v.IsNil() {
+			w.p.SafeString("map[]")
+			return
+		}
+		if !w.enter(v) {
+			return
+		}
+		defer w.leave(v)
+		w.p.SafeString("map[")
+		sorted := fmtsort.Sort(v)
+		for i, key := range sorted.Key {
+			if i > 0 {
+				w.p.SafeRune(' ')
+			}
+			w.leaf(key, true)
+			w.p.SafeRune(':')
+			if key.Kind() == reflect.String && isSecretName(key.String()) {
+				w.secret()
+			} else {
+				w.value(sorted.Value[i], safe)
+			}
+		}
+		w.p.SafeRune(']')
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			w.leaf(v, safe)
+			return
+		}
+		w.p.SafeRune('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				w.p.SafeRune(' ')
+			}
+			w.value(v.Index(i), safe)
+		}
+		w.p.SafeRune(']')
+
+	default:
+		w.leaf(v, safe)
+	}
+}
+
+// leaf prints v as by Print, safely if safe is set.
+func (w *configWalker) leaf(v reflect.Value, safe bool) {
+	if safe {
+		w.p.Print(Safe(v))
+	} else {
+		w.p.Print(v)
+	}
+}
+
+// secret prints the placeholder of a secret value.
+func (w *configWalker) secret() {
+	w.p.Print(RedactableString(RedactedMarker()))
+}
+
+// enter marks the pointer or map v as being printed. It returns false,
+// after printing a placeholder, if v is already being printed.
+func (w *configWalker) enter(v reflect.Value) bool {
+	if w.visiting[v.Pointer()] {
+		w.p.SafeString("<cycle>")
+		return false
+	}
+	w.visiting[v.Pointer()] = true
+	return true
+}
+
+func (w *configWalker) leave(v reflect.Value) { delete(w.visiting, v.Pointer()) }
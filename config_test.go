@@ -0,0 +1,109 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package redact
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type testCredential string
+
+func (testCredential) SecretValue() {}
+
+type testConfig struct {
+	Name     string
+	Port     int
+	Password string
+	APIToken string `json:"api_token"`
+	Cert     string `redact:"secret"`
+	Region   string `redact:"safe"`
+	Cred     testCredential
+	Timeout  time.Duration
+	Nodes    []*testNode
+	Env      map[string]string
+	Limits   map[string]int `redact:"safe"`
+	Next     *testConfig
+	Extra    interface{}
+	raw      []byte
+}
+
+type testNode struct {
+	Addr string
+	Key  string
+}
+
+func TestConfig(t *testing.T) {
+	c := &testConfig{
+		Name:     "prod",
+		Port:     5432,
+		Password: "hunter2",
+		APIToken: "tok",
+		Cert:     "-----BEGIN",
+		Region:   "eu",
+		Cred:     "s3cr3t",
+		Timeout:  time.Second,
+		Nodes:    []*testNode{{Addr: "10.0.0.1", Key: "k1"}, nil},
+		Env:      map[string]string{"HOME": "/home/alice", "db_passwd": "pw", "AWS_SECRET_ACCESS_KEY": "aws"},
+		Limits:   map[string]int{"conns": 10},
+		Extra:    Safe("visible"),
+		raw:      []byte("raw"),
+	}
+	c.Next = c
+
+	const exp = `&{Name:‹prod› Port:‹5432› Password:‹×› APIToken:‹×› Cert:‹×› Region:eu Cred:‹×› ` +
+		`Timeout:‹1s› Nodes:[&{Addr:‹10.0.0.1› Key:‹k1›} <nil>] ` +
+		`Env:map[AWS_SECRET_ACCESS_KEY:‹×› HOME:‹/home/alice› db_passwd:‹×›] Limits:map[conns:10] ` +
+		`Next:<cycle> Extra:visible raw:[‹114› ‹97› ‹119›]}`
+	actual := Sprint(Config(c))
+	if actual != exp {
+		t.Errorf("expected:\n%s\ngot:\n%s", exp, actual)
+	}
+	// The secrets do not appear even before redaction.
+	s := actual.StripMarkers()
+	for _, secret := range []string{"hunter2", "tok", "BEGIN", "s3cr3t", "pw", "aws"} {
+		if strings.Contains(s, secret) {
+			t.Errorf("secret %q printed: %s", secret, s)
+		}
+	}
+
+	if actual := Sprint(Config(nil)); actual != "<nil>" {
+		t.Errorf("unexpected nil rendering: %q", actual)
+	}
+	if actual := Sprint(Config(map[string]int(nil))); actual != "map[]" {
+		t.Errorf("unexpected nil map rendering: %q", actual)
+	}
+}
+
+func TestIsSecretName(t *testing.T) {
+	for _, name := range []string{
+		"Password", "db_passwd", "APIToken", "apiToken", "token", "access_token",
+		"ClientSecret", "AWS_SECRET_ACCESS_KEY", "PrivateKey", "private-key", "apikey",
+		"api.key", "Credentials", "HTTPSecret",
+	} {
+		if !isSecretName(name) {
+			t.Errorf("%s: expected a secret name", name)
+		}
+	}
+	for _, name := range []string{
+		"MaxTokens", "TokenBucketSize", "KeySpan", "Key", "key_count", "SecretName",
+		"PasswordFile", "Name", "Monkey", "tokenizer",
+	} {
+		if isSecretName(name) {
+			t.Errorf("%s: unexpected secret name", name)
+		}
+	}
+}
@@ -213,6 +213,14 @@ type HashBytes []byte
 // HashValue makes HashBytes a HashValue.
 func (HashBytes) HashValue() {}
 
+// SecretValue is a marker interface to be implemented by types whose
+// values are secrets, such as credentials. Unlike unsafe data, which
+// is still present before redaction, secret values are not printed at
+// all by the formatters that recognize them.
+type SecretValue interface {
+	SecretValue()
+}
+
 // SafeMessager is an alternative to SafeFormatter used in previous
 // versions of CockroachDB.
 // NB: this interface is obsolete. Use SafeFormatter instead.
@@ -154,7 +154,10 @@ func (b *Buffer) WriteByte(s byte) error {
 	b.startWrite()
 	if b.mode == UnsafeEscaped &&
 		(s >= utf8.RuneSelf ||
-			s == m.StartS[0] || s == m.EndS[0]) {
+			s == m.StartS[0] || s == m.EndS[0]) &&
+		// With lossless escaping, the markers formed by the
+		// byte are escaped reversibly by escapeToEnd.
+		!m.IsLosslessEscapingEnabled() {
 		// Unsafe byte. Escape it.
 		_, err := b.WriteString(m.EscapeMarkS)
 		return err
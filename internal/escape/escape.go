@@ -31,6 +31,10 @@ import (
 //
 // If strip is set, final newlines and spaces are trimmed from the
 // output.
+//
+// If lossless escaping is enabled, the escaped bytes are encoded
+// reversibly instead of being replaced by the escape mark. See
+// markers.EnableLosslessEscaping.
 func InternalEscapeBytes(b []byte, startLoc int, breakNewLines, strip bool) (res []byte) {
 	// Note: we use len(...RedactableS) and not len(...RedactableBytes)
 	// because the ...S variant is a compile-time constant so this
@@ -39,6 +43,13 @@ func InternalEscapeBytes(b []byte, startLoc int, breakNewLines, strip bool) (res
 	end, le := m.EndBytes, len(m.EndS)
 	hashPrefix, lh := m.HashPrefixBytes, len(m.HashPrefixS)
 	escape := m.EscapeMarkBytes
+	lossless := m.IsLosslessEscapingEnabled()
+	appendEscaped := func(res, esc []byte) []byte {
+		if lossless {
+			return m.AppendLosslessEscape(res, esc)
+		}
+		return append(res, escape...)
+	}
 
 	// Trim final newlines/spaces, for convenience.
 	if strip {
@@ -102,7 +113,7 @@ func InternalEscapeBytes(b []byte, startLoc int, breakNewLines, strip bool) (res
 				copied = true
 			}
 			res = append(res, b[k:i]...)
-			res = append(res, escape...)
+			res = appendEscaped(res, b[i:i+ls])
 			// Advance the counters by the length (in bytes) of the delimiter.
 			k = i + ls
 			i += ls - 1 /* -1 because we have i++ at the end of every iteration */
@@ -113,7 +124,7 @@ func InternalEscapeBytes(b []byte, startLoc int, breakNewLines, strip bool) (res
 				copied = true
 			}
 			res = append(res, b[k:i]...)
-			res = append(res, escape...)
+			res = appendEscaped(res, b[i:i+le])
 			// Advance the counters by the length (in bytes) of the delimiter.
 			k = i + le
 			i += le - 1 /* -1 because we have i++ at the end of every iteration */
@@ -123,9 +134,20 @@ func InternalEscapeBytes(b []byte, startLoc int, breakNewLines, strip bool) (res
 				copied = true
 			}
 			res = append(res, b[k:i]...)
-			res = append(res, escape...)
+			res = appendEscaped(res, b[i:i+lh])
 			k = i + lh
 			i += lh - 1
+		} else if lossless && b[i] == m.EscapeMark && (i+1 == len(b) || b[i+1] == '{') {
+			// With lossless escaping, the escape mark must be encoded
+			// when it could be mistaken for an encoded sequence,
+			// including when the data that follows it starts with {.
+			if !copied {
+				res = make([]byte, 0, len(b)+len(escape))
+				copied = true
+			}
+			res = append(res, b[k:i]...)
+			res = m.AppendLosslessEscape(res, b[i:i+1])
+			k = i + 1
 		}
 	}
 	// If the string terminates with an invalid utf-8 sequence, we
@@ -136,8 +158,13 @@ func InternalEscapeBytes(b []byte, startLoc int, breakNewLines, strip bool) (res
 			res = make([]byte, 0, len(b)+len(escape))
 			copied = true
 		}
-		res = append(res, b[k:]...)
-		res = append(res, escape...)
+		if lossless && len(b)-1 >= startLoc {
+			res = append(res, b[k:len(b)-1]...)
+			res = m.AppendLosslessEscape(res, b[len(b)-1:])
+		} else {
+			res = append(res, b[k:]...)
+			res = append(res, escape...)
+		}
 		k = len(b)
 	}
 	if copied {
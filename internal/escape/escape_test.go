@@ -14,7 +14,11 @@
 
 package escape
 
-import "testing"
+import (
+	"testing"
+
+	m "github.com/cockroachdb/redact/internal/markers"
+)
 
 func TestInternalEscape(t *testing.T) {
 	testCases := []struct {
@@ -49,3 +53,33 @@ func TestInternalEscape(t *testing.T) {
 		}
 	}
 }
+
+func TestInternalEscapeLossless(t *testing.T) {
+	m.EnableLosslessEscaping()
+	defer m.DisableLosslessEscaping()
+
+	testCases := []struct {
+		input    []byte
+		start    int
+		bnl      bool
+		expected string
+	}{
+		{[]byte("abc"), 0, false, "abc"},
+		{[]byte("‹abc›"), 0, false, "?{e280b9}abc?{e280ba}"},
+		{[]byte("‹abc›"), 3, false, "‹abc?{e280ba}"},
+		{[]byte("†abc"), 0, false, "?{e280a0}abc"},
+		{[]byte("a?b"), 0, false, "a?b"},
+		{[]byte("a?{b"), 0, false, "a?{3f}{b"},
+		{[]byte("ab?"), 0, false, "ab?{3f}"},
+		{[]byte("ab?"), 3, false, "ab?"},
+		{[]byte("ab\xe2"), 0, false, "ab?{e2}"},
+		{[]byte("ab\xe2"), 3, false, "ab\xe2?"},
+		{[]byte("a?\nb?"), 0, true, "a?›\n‹b?{3f}"},
+	}
+	for _, tc := range testCases {
+		actual := string(InternalEscapeBytes(tc.input, tc.start, tc.bnl, false))
+		if actual != tc.expected {
+			t.Errorf("%q/%d: expected %q, got %q", string(tc.input), tc.start, tc.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package markers
+
+import "strings"
+
+// Canonicalize returns the normal form of the RedactableString. Two
+// redactable strings that differ only by the way their unsafe regions
+// are delimited have the same normal form. In the normal form:
+//
+//   - adjacent unsafe regions are merged, as by the printing
+//     functions: ‹a›‹b› becomes ‹ab›;
+//   - the empty unsafe regions ‹› and ‹†› are removed;
+//   - the unsafe regions do not contain newlines: ‹a\nb› becomes
+//     ‹a›\n‹b›, as printed by the printing functions;
+//   - the stray marker characters are removed, and the malformed
+//     markers are repaired as by Repair.
+//
+// The regions marked for hashing and the redacted markers are not
+// merged, as this would change the result of Redact.
+func (s RedactableString) Canonicalize() RedactableString {
+	return RedactableString(canonicalBytes([]byte(s)))
+}
+
+// Equal returns true if the normal forms of the two redactable strings
+// are equal. See Canonicalize.
+func (s RedactableString) Equal(other RedactableString) bool {
+	return s == other || s.Canonicalize() == other.Canonicalize()
+}
+
+// Canonicalize returns the normal form of the RedactableBytes. See
+// RedactableString.Canonicalize.
+func (s RedactableBytes) Canonicalize() RedactableBytes {
+	return RedactableBytes(canonicalBytes([]byte(s)))
+}
+
+// Equal returns true if the normal forms of the two redactable strings
+// are equal. See RedactableString.Canonicalize.
+func (s RedactableBytes) Equal(other RedactableBytes) bool {
+	return string(s.Canonicalize()) == string(other.Canonicalize())
+}
+
+func canonicalBytes(data []byte) []byte {
+	buf := make([]byte, 0, len(data))
+	// open is true when buf ends with an unsafe region that has not
+	// been closed yet, so that the next unsafe text can be merged into
+	// it.
+	open := false
+	closeRegion := func() {
+		if open {
+			buf = append(buf, EndS...)
+			open = false
+		}
+	}
+	for _, sp := range spansBytes(data) {
+		switch sp.Kind {
+		case SafeSpan:
+			if sp.Text != "" {
+				closeRegion()
+				buf = append(buf, sp.Text...)
+			}
+		case UnsafeSpan:
+			for i, line := range strings.Split(sp.Text, "\n") {
+				if i > 0 {
+					closeRegion()
+					buf = append(buf, '\n')
+				}
+				if line == "" {
+					continue
+				}
+				if !open {
+					buf = append(buf, StartS...)
+					open = true
+				}
+				buf = append(buf, line...)
+			}
+		case HashSpan:
+			closeRegion()
+			if sp.Text != "" {
+				buf = append(buf, StartS...)
+				buf = append(buf, HashPrefixS...)
+				buf = append(buf, sp.Text...)
+				buf = append(buf, EndS...)
+			}
+		case RedactedSpan:
+			closeRegion()
+			buf = append(buf, RedactedS...)
+		}
+	}
+	closeRegion()
+	return buf
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package markers
+
+import "testing"
+
+func TestCanonicalize(t *testing.T) {
+	testCases := []struct {
+		input, expected string
+	}{
+		{"", ""},
+		{"safe", "safe"},
+		{"‹a›‹b›", "‹ab›"},
+		{"x ‹a›‹›‹b› y", "x ‹ab› y"},
+		{"‹›‹†›", ""},
+		{"‹a\nb›", "‹a›\n‹b›"},
+		{"‹a\n›\n‹\nb›", "‹a›\n\n\n‹b›"},
+		{"‹†a›‹b›‹†c›", "‹†a›‹b›‹†c›"},
+		{"‹†a\nb›", "‹†a\nb›"},
+		{"‹a›‹×›‹×›", "‹a›‹×›‹×›"},
+		{"a†b ‹c†d›", "ab ‹cd›"},
+		{"‹a ‹b", "‹a ?b›"},
+	}
+	for _, tc := range testCases {
+		actual := RedactableString(tc.input).Canonicalize()
+		if string(actual) != tc.expected {
+			t.Errorf("%q: expected %q, got %q", tc.input, tc.expected, actual)
+		}
+		if again := actual.Canonicalize(); again != actual {
+			t.Errorf("%q: not idempotent: %q", tc.input, again)
+		}
+		if b := RedactableBytes(tc.input).Canonicalize(); string(b) != tc.expected {
+			t.Errorf("%q: bytes: expected %q, got %q", tc.input, tc.expected, b)
+		}
+		if !RedactableString(tc.input).Equal(RedactableString(tc.expected)) ||
+			!RedactableBytes(tc.input).Equal(RedactableBytes(tc.expected)) {
+			t.Errorf("%q: expected equal to %q", tc.input, tc.expected)
+		}
+	}
+
+	for _, tc := range []struct{ a, b string }{
+		{"‹a›b", "‹ab›"},
+		{"‹a›", "a"},
+		{"‹†a›", "‹a›"},
+		{"‹×›", "‹a›"},
+	} {
+		if RedactableString(tc.a).Equal(RedactableString(tc.b)) {
+			t.Errorf("%q and %q: expected not equal", tc.a, tc.b)
+		}
+	}
+}
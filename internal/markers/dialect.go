@@ -0,0 +1,299 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package markers
+
+import "bytes"
+
+// Dialect is a representation of the redaction markers in a
+// redactable string.
+//
+// The Unicode dialect, used by RedactableString and RedactableBytes,
+// delimits the unsafe regions with ‹ and ›. The ASCII dialect is meant
+// for the systems that mangle or reject non-ASCII characters. Use
+// ConvertDialect to convert between the two.
+type Dialect struct {
+	name                                 string
+	start, end, hashPrefix, redactedMark []byte
+	// scan returns the token at the beginning of the non-empty data,
+	// and its length. For text tokens, lit is the text represented by
+	// the token, once unescaped.
+	scan func(data []byte) (tok token, n int, lit []byte)
+	// escape appends the text s to buf, escaped so that it does not
+	// contain markers.
+	escape func(buf, s []byte) []byte
+}
+
+// token is a lexical element of a redactable string.
+type token int
+
+const (
+	tokText token = iota
+	tokStart
+	tokEnd
+	tokHashPrefix
+)
+
+// UnicodeDialect is the dialect of RedactableString and
+// RedactableBytes. Markers inside the data are escaped by replacing
+// them with ?, so the escaping is not reversible.
+var UnicodeDialect = &Dialect{
+	name:         "unicode",
+	start:        StartBytes,
+	end:          EndBytes,
+	hashPrefix:   HashPrefixBytes,
+	redactedMark: RedactedBytes,
+	scan:         scanUnicode,
+	escape: func(buf, s []byte) []byte {
+		return append(buf, stripMarkersBytes(s, EscapeMarkBytes)...)
+	},
+}
+
+// ASCIIDialect is a dialect using only ASCII characters. The markers
+// are escape sequences introduced by a backslash:
+//
+//	\[  starts an unsafe region (‹)
+//	\]  ends an unsafe region (›)
+//	\#  marks the region for hashing (†)
+//	\\  is a literal backslash
+//
+// The redacted marker is \[\*\], where \* stands for the redacted
+// data and becomes * once the markers are stripped. Since literal
+// backslashes are doubled, the escaping is reversible.
+var ASCIIDialect = &Dialect{
+	name:         "ascii",
+	start:        []byte(`\[`),
+	end:          []byte(`\]`),
+	hashPrefix:   []byte(`\#`),
+	redactedMark: []byte(`\[\*\]`),
+	scan:         scanASCII,
+	escape: func(buf, s []byte) []byte {
+		for {
+			i := bytes.IndexByte(s, '\\')
+			if i == -1 {
+				return append(buf, s...)
+			}
+			buf = append(buf, s[:i+1]...)
+			buf = append(buf, '\\')
+			s = s[i+1:]
+		}
+	},
+}
+
+// String returns the name of the dialect.
+func (d *Dialect) String() string { return d.name }
+
+// StartMarker returns the start delimiter for an unsafe string.
+func (d *Dialect) StartMarker() []byte { return append([]byte(nil), d.start...) }
+
+// EndMarker returns the end delimiter for an unsafe string.
+func (d *Dialect) EndMarker() []byte { return append([]byte(nil), d.end...) }
+
+// HashPrefixMarker returns the prefix that marks an unsafe string
+// for hashing instead of full redaction.
+func (d *Dialect) HashPrefixMarker() []byte { return append([]byte(nil), d.hashPrefix...) }
+
+// RedactedMarker returns the special string used by Redact.
+func (d *Dialect) RedactedMarker() []byte { return append([]byte(nil), d.redactedMark...) }
+
+// EscapeMarkers escapes the special delimiters from the provided
+// byte slice.
+func (d *Dialect) EscapeMarkers(s []byte) []byte {
+	if d == UnicodeDialect {
+		return EscapeMarkers(s)
+	}
+	return d.escape(nil, s)
+}
+
+// StripMarkers removes the redaction markers from s, a redactable
+// string in the dialect, and unescapes the remaining text.
+func (d *Dialect) StripMarkers(s []byte) []byte {
+	if d == UnicodeDialect {
+		return stripMarkersBytes(s, nil)
+	}
+	return d.unescape(nil, s)
+}
+
+// Redact replaces the unsafe regions of s, a redactable string in the
+// dialect, by the redacted marker, or by the hashed value for the
+// regions marked for hashing if hashing is enabled. See
+// RedactableBytes.Redact.
+func (d *Dialect) Redact(s []byte) []byte {
+	if d == UnicodeDialect {
+		return redactBytes(s)
+	}
+	hashEnabled := IsHashingEnabled()
+	var buf []byte
+	pos := 0
+	for i := 0; i < len(s); {
+		tok, n, _ := d.scan(s[i:])
+		if tok != tokStart {
+			i += n
+			continue
+		}
+		contentStart := i + n
+		contentEnd := d.indexEnd(s, contentStart)
+		if contentEnd == -1 {
+			// Like RedactableBytes.Redact, preserve an unclosed
+			// region. Use Validate to detect this case.
+			break
+		}
+		if buf == nil {
+			buf = make([]byte, 0, len(s))
+		}
+		buf = append(buf, s[pos:i]...)
+		content := s[contentStart:contentEnd]
+		if hashEnabled && bytes.HasPrefix(content, d.hashPrefix) {
+			// The hashed bytes are those of the value in the Unicode
+			// dialect, so that the hash does not depend on the dialect
+			// and is preserved by ConvertDialect.
+			value := d.unescape(nil, content[len(d.hashPrefix):])
+			buf = append(buf, d.start...)
+			buf = appendHash(buf, UnicodeDialect.escape(nil, value))
+			buf = append(buf, d.end...)
+		} else {
+			buf = append(buf, d.redactedMark...)
+		}
+		i = contentEnd + len(d.end)
+		pos = i
+	}
+	if buf == nil {
+		return s
+	}
+	return append(buf, s[pos:]...)
+}
+
+// Validate checks that the redaction markers in s, a redactable
+// string in the dialect, are balanced. See RedactableString.Validate.
+func (d *Dialect) Validate(s []byte) error {
+	return validateBytes(d, s)
+}
+
+// indexEnd returns the position of the first end marker in s at or
+// after pos, or -1.
+func (d *Dialect) indexEnd(s []byte, pos int) int {
+	for i := pos; i < len(s); {
+		tok, n, _ := d.scan(s[i:])
+		if tok == tokEnd {
+			return i
+		}
+		i += n
+	}
+	return -1
+}
+
+// unescape appends the text of s to buf, without the markers.
+func (d *Dialect) unescape(buf, s []byte) []byte {
+	for i := 0; i < len(s); {
+		tok, n, lit := d.scan(s[i:])
+		if tok == tokText {
+			buf = append(buf, lit...)
+		}
+		i += n
+	}
+	return buf
+}
+
+// ConvertDialect converts s, a redactable string in the dialect from,
+// to the dialect to. The redacted markers are converted to the
+// redacted marker of the target dialect.
+//
+// The conversion from the ASCII dialect to the Unicode dialect is
+// lossy when the data contains the characters ‹, › or †, as they are
+// escaped in the Unicode dialect.
+func ConvertDialect(s []byte, from, to *Dialect) []byte {
+	if from == to {
+		return s
+	}
+	buf := make([]byte, 0, len(s))
+	for i := 0; i < len(s); {
+		tok, n, lit := from.scan(s[i:])
+		switch tok {
+		case tokText:
+			buf = to.escape(buf, lit)
+		case tokStart:
+			if bytes.HasPrefix(s[i:], from.redactedMark) {
+				buf = append(buf, to.redactedMark...)
+				n = len(from.redactedMark)
+			} else {
+				buf = append(buf, to.start...)
+			}
+		case tokEnd:
+			buf = append(buf, to.end...)
+		case tokHashPrefix:
+			buf = append(buf, to.hashPrefix...)
+		}
+		i += n
+	}
+	return buf
+}
+
+// isUnicodeMarker returns true if data starts with a marker character
+// of the Unicode dialect.
+func isUnicodeMarker(data []byte) (token, bool) {
+	if len(data) < markerLen || data[0] != StartBytes[0] || data[1] != StartBytes[1] {
+		return tokText, false
+	}
+	switch data[2] {
+	case StartBytes[2]:
+		return tokStart, true
+	case EndBytes[2]:
+		return tokEnd, true
+	case HashPrefixBytes[2]:
+		return tokHashPrefix, true
+	}
+	return tokText, false
+}
+
+func scanUnicode(data []byte) (tok token, n int, lit []byte) {
+	if tok, ok := isUnicodeMarker(data); ok {
+		return tok, markerLen, nil
+	}
+	// The text extends up to the next marker. All marker characters
+	// share the same leading UTF-8 byte.
+	for n = 1; n < len(data); n++ {
+		j := bytes.IndexByte(data[n:], StartBytes[0])
+		if j == -1 {
+			n = len(data)
+			break
+		}
+		n += j
+		if _, ok := isUnicodeMarker(data[n:]); ok {
+			break
+		}
+	}
+	return tokText, n, data[:n]
+}
+
+func scanASCII(data []byte) (tok token, n int, lit []byte) {
+	if data[0] == '\\' && len(data) > 1 {
+		switch data[1] {
+		case '[':
+			return tokStart, 2, nil
+		case ']':
+			return tokEnd, 2, nil
+		case '#':
+			return tokHashPrefix, 2, nil
+		case '\\', '*':
+			return tokText, 2, data[1:2]
+		}
+	}
+	// The text extends up to the next backslash. A backslash that does
+	// not start an escape sequence is literal.
+	n = len(data)
+	if j := bytes.IndexByte(data[1:], '\\'); j != -1 {
+		n = j + 1
+	}
+	return tokText, n, data[:n]
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package markers
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConvertDialect(t *testing.T) {
+	testCases := []struct {
+		unicode string
+		ascii   string
+	}{
+		{"", ""},
+		{"hello world", "hello world"},
+		{"a ‹b› c", `a \[b\] c`},
+		{"‹†alice›", `\[\#alice\]`},
+		{"x ‹×› y", `x \[\*\] y`},
+		{"‹×y›", `\[×y\]`},
+		{`C:\dir ‹C:\user›`, `C:\\dir \[C:\\user\]`},
+		{"‹a*b› * €", `\[a*b\] * €`},
+		{"‹unclosed", `\[unclosed`},
+		{"stray› ?", `stray\] ?`},
+	}
+	for _, tc := range testCases {
+		if actual := string(ConvertDialect([]byte(tc.unicode), UnicodeDialect, ASCIIDialect)); actual != tc.ascii {
+			t.Errorf("%q: expected %q, got %q", tc.unicode, tc.ascii, actual)
+		}
+		if actual := string(ConvertDialect([]byte(tc.ascii), ASCIIDialect, UnicodeDialect)); actual != tc.unicode {
+			t.Errorf("%q: expected %q, got %q", tc.ascii, tc.unicode, actual)
+		}
+		// Redaction and conversion commute.
+		a := string(ASCIIDialect.Redact([]byte(tc.ascii)))
+		u := string(ConvertDialect(RedactableBytes(tc.unicode).Redact(), UnicodeDialect, ASCIIDialect))
+		if a != u {
+			t.Errorf("%q: redaction mismatch: %q vs %q", tc.unicode, a, u)
+		}
+		// So do stripping and conversion, except for the redacted
+		// marker which is stripped to * in the ASCII dialect.
+		a = string(ASCIIDialect.StripMarkers([]byte(tc.ascii)))
+		u = string(RedactableBytes(tc.unicode).StripMarkers())
+		if a != u && !strings.Contains(tc.unicode, RedactedS) {
+			t.Errorf("%q: strip mismatch: %q vs %q", tc.unicode, a, u)
+		}
+	}
+
+	// Marker characters in the ASCII dialect are escaped in the Unicode
+	// dialect.
+	if actual := string(ConvertDialect([]byte(`‹a› \[‹b›\]`), ASCIIDialect, UnicodeDialect)); actual != "?a? ‹?b?›" {
+		t.Errorf("unexpected conversion: %q", actual)
+	}
+	// Unknown escape sequences are literal.
+	if actual := string(ASCIIDialect.StripMarkers([]byte(`\n\[\x\]\`))); actual != `\n\x\` {
+		t.Errorf("unexpected strip: %q", actual)
+	}
+}
+
+func TestDialectRedactHash(t *testing.T) {
+	EnableHashing([]byte("salt"))
+	defer DisableHashing()
+	hash := string(appendHash(nil, []byte(`a\b`)))
+
+	actual := string(ASCIIDialect.Redact([]byte(`x \[\#a\\b\] \[c\] \[\#\]`)))
+	exp := `x \[` + hash + `\] \[\*\] \[` + string(appendHash(nil, nil)) + `\]`
+	if actual != exp {
+		t.Errorf("expected %q, got %q", exp, actual)
+	}
+	if u := string(RedactableString(`‹†a\b›`).Redact()); u != "‹"+hash+"›" {
+		t.Errorf("unicode and ascii hashes differ: %q", u)
+	}
+
+	// The hashes are preserved by the conversion in both directions,
+	// including when the value contains marker characters, which the
+	// Unicode dialect escapes.
+	for _, a := range []string{`\[\#a\\b\]`, `\[\#x‹y›\]`, `\[\#†\]`} {
+		u := ConvertDialect([]byte(a), ASCIIDialect, UnicodeDialect)
+		back := ConvertDialect(u, UnicodeDialect, ASCIIDialect)
+		ah := string(ASCIIDialect.Redact([]byte(a)))
+		uh := string(ConvertDialect(RedactableBytes(u).Redact(), UnicodeDialect, ASCIIDialect))
+		bh := string(ASCIIDialect.Redact(back))
+		if ah != uh || bh != uh {
+			t.Errorf("%q: hashes differ: ascii %q, unicode %q, round trip %q", a, ah, uh, bh)
+		}
+	}
+}
+
+func TestDialectValidate(t *testing.T) {
+	err := ASCIIDialect.Validate([]byte(`a\] \[b \[c`))
+	var me *MarkerError
+	if !errors.As(err, &me) {
+		t.Fatalf("expected MarkerError, got %v", err)
+	}
+	exp := []MarkerProblem{{1, UnmatchedEndMarker}, {4, UnclosedStartMarker}, {8, NestedStartMarker}}
+	if !reflect.DeepEqual(me.Problems, exp) {
+		t.Errorf("expected %+v, got %+v", exp, me.Problems)
+	}
+	if err := ASCIIDialect.Validate([]byte(`\\[a\\]`)); err != nil {
+		t.Errorf("escaped backslashes are not markers: %v", err)
+	}
+}
+
+func TestDialectMarkers(t *testing.T) {
+	for _, tc := range []struct {
+		d                              *Dialect
+		start, end, hash, red, escaped string
+	}{
+		{UnicodeDialect, "‹", "›", "†", "‹×›", `?a\?`},
+		{ASCIIDialect, `\[`, `\]`, `\#`, `\[\*\]`, `‹a\\›`},
+	} {
+		t.Run(tc.d.String(), func(t *testing.T) {
+			if s := string(tc.d.StartMarker()); s != tc.start {
+				t.Errorf("start: %q", s)
+			}
+			if s := string(tc.d.EndMarker()); s != tc.end {
+				t.Errorf("end: %q", s)
+			}
+			if s := string(tc.d.HashPrefixMarker()); s != tc.hash {
+				t.Errorf("hash prefix: %q", s)
+			}
+			if s := string(tc.d.RedactedMarker()); s != tc.red {
+				t.Errorf("redacted: %q", s)
+			}
+			if s := string(tc.d.EscapeMarkers([]byte(`‹a\›`))); s != tc.escaped {
+				t.Errorf("escaped: %q", s)
+			}
+		})
+	}
+}
@@ -63,6 +63,7 @@ type hasherState struct {
 
 var hashConfig struct {
 	enabled atomic.Bool
+	salted  atomic.Bool
 	pool    atomic.Value
 }
 
@@ -92,6 +93,7 @@ func EnableHashing(salt []byte) {
 	}
 
 	hashConfig.pool.Store(pool)
+	hashConfig.salted.Store(len(salt) > 0)
 	hashConfig.enabled.Store(true)
 }
 
@@ -124,3 +126,15 @@ func appendHash(dst []byte, value []byte) []byte {
 	p.Put(state)
 	return dst
 }
+
+// AppendFingerprint appends a truncated HMAC-SHA256 of value, keyed
+// with the salt, to dst. It returns false and dst unchanged unless
+// hashing is enabled with a salt: the fingerprint is printed as safe
+// text, and an unkeyed hash of a short value, such as a PIN, could be
+// reversed by brute force.
+func AppendFingerprint(dst []byte, value []byte) ([]byte, bool) {
+	if !IsHashingEnabled() || !hashConfig.salted.Load() {
+		return dst, false
+	}
+	return appendHash(dst, value), true
+}
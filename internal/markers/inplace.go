@@ -0,0 +1,131 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package markers
+
+import "bytes"
+
+// RedactInPlace is like Redact but reuses the storage of s for the
+// result. The contents of s are overwritten and s must not be used
+// afterwards; use the returned slice instead.
+//
+// The redaction is usually shorter than the input, and then it does
+// not allocate. However, the regions shorter than the redacted marker
+// (e.g. ‹a›, which becomes ‹×›) and, when hashing is enabled, the hash
+// regions with a short value grow, so the result can be longer than s.
+// In that case, the output from the first such region onwards is built
+// in a temporary buffer, which allocates when it exceeds 256 bytes,
+// and the result is reallocated when it exceeds the capacity of s.
+func (s RedactableBytes) RedactInPlace() RedactableBytes {
+	return RedactableBytes(redactInPlace([]byte(s)))
+}
+
+// StripMarkersInPlace is like StripMarkers but reuses the storage of
+// s for the result, which is never longer than s. The contents of s
+// are overwritten and s must not be used afterwards; use the returned
+// slice instead.
+func (s RedactableBytes) StripMarkersInPlace() []byte {
+	return stripMarkersInPlace([]byte(s))
+}
+
+// redactInPlace rewrites the redaction of data at the beginning of
+// data. The output of each region is written as long as it does not
+// overtake the end of the region, so that the data not yet read is
+// left intact.
+func redactInPlace(data []byte) []byte {
+	r := bytes.Index(data, StartBytes)
+	if r == -1 {
+		return data
+	}
+	hashEnabled := IsHashingEnabled()
+	w := r
+	for {
+		contentStart := r + StartLen
+		j := bytes.Index(data[contentStart:], EndBytes)
+		if j == -1 {
+			// Like Redact, preserve an unclosed region.
+			w += copy(data[w:], data[r:])
+			return data[:w]
+		}
+		content := data[contentStart : contentStart+j]
+		regionEnd := contentStart + j + EndLen
+		hash := hashEnabled && bytes.HasPrefix(content, HashPrefixBytes)
+		outLen := len(RedactedBytes)
+		if hash {
+			outLen = StartLen + defaultHashLength + EndLen
+		}
+		if w+outLen > regionEnd {
+			return redactTail(data, w, r)
+		}
+		if hash {
+			// The hash is computed before it is written, and the start
+			// marker written first ends before the value.
+			out := append(data[:w], StartBytes...)
+			out = appendHash(out, content[len(HashPrefixBytes):])
+			out = append(out, EndBytes...)
+			w = len(out)
+		} else {
+			w += copy(data[w:], RedactedBytes)
+		}
+		next := bytes.Index(data[regionEnd:], StartBytes)
+		if next == -1 {
+			w += copy(data[w:], data[regionEnd:])
+			return data[:w]
+		}
+		w += copy(data[w:], data[regionEnd:regionEnd+next])
+		r = regionEnd + next
+	}
+}
+
+// redactTail completes redactInPlace when the output of the region
+// starting at r would overwrite data not yet read. The rest of the
+// data is redacted in a scratch buffer first, which is on the stack up
+// to 256 bytes, then copied after the output already written at w.
+// The result is reallocated if it does not fit in the capacity of
+// data.
+func redactTail(data []byte, w, r int) []byte {
+	var scratch [256]byte
+	tail := appendRedacted(scratch[:0], data[r:], 0)
+	if w+len(tail) <= cap(data) {
+		return append(data[:w], tail...)
+	}
+	return append(data[:w:w], tail...)
+}
+
+// stripMarkersInPlace removes the marker characters from data,
+// overwriting it.
+func stripMarkersInPlace(data []byte) []byte {
+	lead := StartBytes[0] // first byte shared by all marker chars
+	i := bytes.IndexByte(data, lead)
+	if i == -1 {
+		return data
+	}
+	w := i
+	for i < len(data) {
+		if _, ok := isUnicodeMarker(data[i:]); ok {
+			i += markerLen
+		} else {
+			data[w] = data[i]
+			w++
+			i++
+		}
+		j := bytes.IndexByte(data[i:], lead)
+		if j == -1 {
+			j = len(data) - i
+		}
+		w += copy(data[w:], data[i:i+j])
+		i += j
+	}
+	return data[:w]
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package markers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInPlace(t *testing.T) {
+	inputs := []string{
+		"",
+		"hello world",
+		"a ‹b› c ‹†d› ‹×› €",
+		"‹› ‹a› ‹ab› ‹abc›",
+		"‹a›‹b›‹c›‹d› tail",
+		"‹†› ‹†x› ‹†alice› ‹†a long value to hash›",
+		"user ‹alice logged in",
+		"‹a› b ‹c",
+		"lice› logged in",
+		"‹a ‹b› c",
+		"x› ‹y ‹z",
+		"† ‹†› €‹",
+		"\xe2\x80 ‹x› \xe2",
+	}
+	check := func(t *testing.T) {
+		for _, in := range inputs {
+			exp := string(RedactableBytes(in).Redact())
+			if actual := string(RedactableBytes(in).RedactInPlace()); actual != exp {
+				t.Errorf("%q: expected %q, got %q", in, exp, actual)
+			}
+			// With extra capacity the growth does not reallocate.
+			b := make([]byte, len(in), len(in)+64)
+			copy(b, in)
+			if actual := RedactableBytes(b).RedactInPlace(); string(actual) != exp {
+				t.Errorf("%q: expected %q, got %q", in, exp, actual)
+			} else if len(actual) > 0 && &actual[0] != &b[0] {
+				t.Errorf("%q: unexpected reallocation", in)
+			}
+
+			exp = string(RedactableBytes(in).StripMarkers())
+			if actual := string(RedactableBytes(in).StripMarkersInPlace()); actual != exp {
+				t.Errorf("%q: expected %q, got %q", in, exp, actual)
+			}
+		}
+	}
+	t.Run("redact", check)
+	t.Run("hash", func(t *testing.T) {
+		EnableHashing(nil)
+		defer DisableHashing()
+		check(t)
+	})
+}
+
+func TestInPlaceGrowth(t *testing.T) {
+	long := strings.Repeat("x", 300)
+	for _, in := range []string{
+		// A 1-byte region at the end grows by 1 byte.
+		"user ‹a›",
+		// The tail after the first growing region exceeds the scratch
+		// buffer.
+		"‹a› " + long + " ‹b› " + long,
+	} {
+		exp := string(RedactableBytes(in).Redact())
+		if len(exp) <= len(in) {
+			t.Fatalf("%q: expected the redaction to grow", in)
+		}
+		// Without spare capacity, the result is reallocated.
+		b := []byte(in)
+		b = b[:len(b):len(b)]
+		if actual := RedactableBytes(b).RedactInPlace(); string(actual) != exp {
+			t.Errorf("%q: expected %q, got %q", in, exp, actual)
+		}
+		// With enough capacity, it reuses the storage of the input.
+		b = make([]byte, len(in), len(exp))
+		copy(b, in)
+		if actual := RedactableBytes(b).RedactInPlace(); string(actual) != exp {
+			t.Errorf("%q: expected %q, got %q", in, exp, actual)
+		} else if &actual[0] != &b[0] {
+			t.Errorf("%q: unexpected reallocation", in)
+		}
+	}
+}
+
+func TestInPlaceAllocs(t *testing.T) {
+	const line = "user ‹alice› logged in from ‹10.0.0.1› at ‹x›"
+	buf := make([]byte, len(line))
+	allocs := testing.AllocsPerRun(100, func() {
+		copy(buf, line)
+		_ = RedactableBytes(buf).RedactInPlace()
+		copy(buf, line)
+		_ = RedactableBytes(buf).StripMarkersInPlace()
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations, got %v", allocs)
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package markers
+
+import (
+	"bytes"
+	"strings"
+	"sync/atomic"
+)
+
+/*
+	Lossless escaping notes:
+
+	By default, the marker characters found inside the data are replaced
+	by the escape mark ?, and a ? is appended after a trailing invalid
+	UTF-8 byte. This loses the original bytes.
+
+	When lossless escaping is enabled, the escaped bytes are instead
+	encoded as ?{hex}, e.g. ‹ becomes ?{e280b9}. For the encoding to be
+	reversible, occurrences of ?{ in the data are encoded too, as
+	?{3f}{. So is a ? at the end of the escaped data, since the data
+	that follows it in the redactable string may start with {. The
+	encoded form contains no marker characters, so the redaction of the
+	unsafe regions is unaffected.
+*/
+
+var losslessEscaping atomic.Bool
+
+// EnableLosslessEscaping enables the reversible escaping of the
+// markers found inside the data. See Unescape.
+func EnableLosslessEscaping() { losslessEscaping.Store(true) }
+
+// DisableLosslessEscaping restores the default escaping, where the
+// markers found inside the data are replaced by ?.
+func DisableLosslessEscaping() { losslessEscaping.Store(false) }
+
+// IsLosslessEscapingEnabled returns true if lossless escaping is
+// enabled.
+func IsLosslessEscapingEnabled() bool { return losslessEscaping.Load() }
+
+// Prefix and suffix of a losslessly escaped byte sequence.
+const (
+	LosslessPrefixS = EscapeMarkS + "{"
+	LosslessSuffixS = "}"
+)
+
+// AppendLosslessEscape appends the lossless encoding of the bytes b to
+// buf.
+func AppendLosslessEscape(buf, b []byte) []byte {
+	buf = append(buf, LosslessPrefixS...)
+	for _, c := range b {
+		buf = append(buf, hexDigits[c>>4], hexDigits[c&0xf])
+	}
+	return append(buf, LosslessSuffixS...)
+}
+
+const hexDigits = "0123456789abcdef"
+
+// escapeLossless is the lossless variant of EscapeMarkers.
+func escapeLossless(s []byte) []byte {
+	var buf []byte
+	k := 0
+	for i := 0; i < len(s); i++ {
+		var n int
+		switch {
+		case s[i] == StartBytes[0] && i+markerLen <= len(s) && s[i+1] == StartBytes[1] &&
+			(s[i+2] == StartBytes[2] || s[i+2] == EndBytes[2] || s[i+2] == HashPrefixBytes[2]):
+			n = markerLen
+		case s[i] == EscapeMark && (i+1 == len(s) || s[i+1] == '{'):
+			n = 1
+		default:
+			continue
+		}
+		if buf == nil {
+			buf = make([]byte, 0, len(s)+16)
+		}
+		buf = append(buf, s[k:i]...)
+		buf = AppendLosslessEscape(buf, s[i:i+n])
+		k = i + n
+		i += n - 1
+	}
+	if buf == nil {
+		return s
+	}
+	return append(buf, s[k:]...)
+}
+
+// Unescape decodes the bytes escaped by lossless escaping in s. It
+// does not remove the markers; see StripMarkersExact.
+//
+// Unescape should only be used on data produced with lossless
+// escaping enabled, as the default escaping is not reversible.
+func Unescape(s []byte) []byte {
+	i := bytes.Index(s, []byte(LosslessPrefixS))
+	if i == -1 {
+		return s
+	}
+	buf := make([]byte, 0, len(s))
+	for i != -1 {
+		buf = append(buf, s[:i]...)
+		s = s[i:]
+		if digits, ok := losslessDigits(s); ok {
+			for j := 0; j < len(digits); j += 2 {
+				buf = append(buf, unhex(digits[j])<<4|unhex(digits[j+1]))
+			}
+			s = s[len(LosslessPrefixS)+len(digits)+len(LosslessSuffixS):]
+		} else {
+			// Not a valid encoding: keep the escape mark as-is.
+			buf = append(buf, s[0])
+			s = s[1:]
+		}
+		i = bytes.Index(s, []byte(LosslessPrefixS))
+	}
+	return append(buf, s...)
+}
+
+// losslessDigits returns the hex digits of the encoded sequence at
+// the start of s, if s starts with a valid encoding.
+func losslessDigits(s []byte) (digits []byte, ok bool) {
+	i := len(LosslessPrefixS)
+	for i < len(s) && ('0' <= s[i] && s[i] <= '9' || 'a' <= s[i] && s[i] <= 'f') {
+		i++
+	}
+	digits = s[len(LosslessPrefixS):i]
+	if len(digits) == 0 || len(digits)%2 != 0 || i == len(s) || s[i] != LosslessSuffixS[0] {
+		return nil, false
+	}
+	return digits, true
+}
+
+func unhex(c byte) byte {
+	if c <= '9' {
+		return c - '0'
+	}
+	return c - 'a' + 10
+}
+
+// StripMarkersExact removes the redaction markers from the
+// RedactableString and decodes the bytes escaped by lossless
+// escaping. When the string was produced with lossless escaping
+// enabled, this returns the original unsafe bytes exactly.
+func (s RedactableString) StripMarkersExact() string {
+	if !strings.Contains(string(s), LosslessPrefixS) {
+		return s.StripMarkers()
+	}
+	return string(Unescape(stripMarkersBytes([]byte(s), nil)))
+}
+
+// StripMarkersExact removes the redaction markers from the
+// RedactableBytes and decodes the bytes escaped by lossless escaping.
+// See RedactableString.StripMarkersExact.
+func (s RedactableBytes) StripMarkersExact() []byte {
+	return Unescape(stripMarkersBytes([]byte(s), nil))
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package markers
+
+import "testing"
+
+func TestUnescape(t *testing.T) {
+	testCases := []struct {
+		input, expected string
+	}{
+		{"", ""},
+		{"abc", "abc"},
+		{"a?b", "a?b"},
+		{"?{41}", "A"},
+		{"x?{e280b9}y?{e280ba}", "x‹y›"},
+		{"?{3f}{41}", "?{41}"},
+		{"?{", "?{"},
+		{"?{}", "?{}"},
+		{"?{4}", "?{4}"},
+		{"?{4g}", "?{4g}"},
+		{"?{41", "?{41"},
+		{"?{ABCD}", "?{ABCD}"},
+		{"??{41}", "?A"},
+	}
+	for _, tc := range testCases {
+		if actual := string(Unescape([]byte(tc.input))); actual != tc.expected {
+			t.Errorf("%q: expected %q, got %q", tc.input, tc.expected, actual)
+		}
+	}
+}
+
+func TestEscapeMarkersLossless(t *testing.T) {
+	EnableLosslessEscaping()
+	defer DisableLosslessEscaping()
+
+	for _, input := range []string{"", "abc", "‹a› †b", "?{41}", "a?", "?", "€?x"} {
+		escaped := EscapeMarkers([]byte(input))
+		if len(stripMarkersBytes(escaped, nil)) != len(escaped) {
+			t.Errorf("%q: escaped to %q, which contains markers", input, escaped)
+		}
+		if actual := string(Unescape(escaped)); actual != input {
+			t.Errorf("%q: expected round trip, got %q from %q", input, actual, escaped)
+		}
+	}
+	if actual := string(RedactableString("‹a?{3f}{b›?{e280b9}").StripMarkersExact()); actual != "a?{b‹" {
+		t.Errorf("unexpected strip: %q", actual)
+	}
+}
@@ -98,12 +98,17 @@ func redactBytes(data []byte) []byte {
 	if idx == -1 {
 		return data
 	}
-	hashEnabled := IsHashingEnabled()
 	// len(data) is exact for the non-hash path (markers always shrink) and a
 	// close lower bound for the hash path. Hash markers with content shorter
 	// than 5 bytes expand slightly (e.g. ‹†x› 10B → ‹abcdef01› 14B), but
 	// this is rare enough that letting append grow is cheaper than a pre-scan.
-	buf := make([]byte, 0, len(data))
+	return appendRedacted(make([]byte, 0, len(data)), data, idx)
+}
+
+// appendRedacted appends the redaction of data to buf. idx is the
+// position of the first start marker in data.
+func appendRedacted(buf, data []byte, idx int) []byte {
+	hashEnabled := IsHashingEnabled()
 	pos := 0
 	for idx != -1 {
 		buf = append(buf, data[pos:pos+idx]...)
@@ -151,9 +156,16 @@ func EndMarker() []byte { return []byte(EndS) }
 // RedactedMarker returns the special string used by Redact.
 func RedactedMarker() []byte { return []byte(RedactedS) }
 
+// HashPrefixMarker returns the prefix that marks an unsafe string
+// for hashing instead of full redaction.
+func HashPrefixMarker() []byte { return []byte(HashPrefixS) }
+
 // EscapeMarkers escapes the special delimiters from the provided
 // byte slice.
 func EscapeMarkers(s []byte) []byte {
+	if IsLosslessEscapingEnabled() {
+		return escapeLossless(s)
+	}
 	return stripMarkersBytes(s, EscapeMarkBytes)
 }
 
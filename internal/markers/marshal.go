@@ -0,0 +1,329 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package markers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/*
+	Marshaling notes:
+
+	The text and JSON encodings store the redactable string with its
+	markers, so that it is not escaped a second time when it is decoded,
+	and the decoding validates the markers.
+
+	The structured JSON form and the binary encoding store the plain
+	text, i.e. without markers, and a table of spans. The structured
+	JSON form is:
+
+	  {"text": "user alice", "spans": [{"kind": "unsafe", "start": 5, "end": 10}]}
+
+	where the offsets are byte offsets in the text, and the safe spans
+	are omitted. The binary encoding is:
+
+	  version byte (1)
+	  number of spans (uvarint)
+	  for each span: kind (byte), length of the text (uvarint)
+	  the texts of the spans, concatenated
+
+	The span texts may not contain markers; they are not escaped when
+	the marker form is rebuilt.
+*/
+
+// MarshalText implements encoding.TextMarshaler.
+func (s RedactableString) MarshalText() ([]byte, error) { return []byte(s), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler. It returns a
+// *MarkerError if the markers are malformed.
+func (s *RedactableString) UnmarshalText(text []byte) error {
+	if err := validateBytes(UnicodeDialect, text); err != nil {
+		return err
+	}
+	*s = RedactableString(text)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, with a JSON string.
+func (s RedactableString) MarshalJSON() ([]byte, error) { return json.Marshal(string(s)) }
+
+// MarshalStructuredJSON returns the structured JSON form of s, with the
+// plain text and the table of its unsafe spans. UnmarshalJSON accepts
+// it. See also StructuredRedactableString.
+func (s RedactableString) MarshalStructuredJSON() ([]byte, error) {
+	return marshalStructuredJSON([]byte(s))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts both a JSON
+// string and the structured form.
+func (s *RedactableString) UnmarshalJSON(data []byte) error {
+	b, err := unmarshalJSON(data)
+	if err != nil {
+		return err
+	}
+	*s = RedactableString(b)
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, with the plain
+// text and the table of spans.
+func (s RedactableString) MarshalBinary() ([]byte, error) { return marshalBinary([]byte(s)), nil }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *RedactableString) UnmarshalBinary(data []byte) error {
+	b, err := unmarshalBinary(data)
+	if err != nil {
+		return err
+	}
+	*s = RedactableString(b)
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s RedactableBytes) MarshalText() ([]byte, error) { return []byte(s), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler. It returns a
+// *MarkerError if the markers are malformed.
+func (s *RedactableBytes) UnmarshalText(text []byte) error {
+	if err := validateBytes(UnicodeDialect, text); err != nil {
+		return err
+	}
+	*s = append((*s)[:0], text...)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, with a JSON string.
+func (s RedactableBytes) MarshalJSON() ([]byte, error) { return json.Marshal(string(s)) }
+
+// MarshalStructuredJSON returns the structured JSON form of s. See
+// RedactableString.MarshalStructuredJSON.
+func (s RedactableBytes) MarshalStructuredJSON() ([]byte, error) {
+	return marshalStructuredJSON([]byte(s))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts both a JSON
+// string and the structured form.
+func (s *RedactableBytes) UnmarshalJSON(data []byte) error {
+	b, err := unmarshalJSON(data)
+	if err != nil {
+		return err
+	}
+	*s = RedactableBytes(b)
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, with the plain
+// text and the table of spans.
+func (s RedactableBytes) MarshalBinary() ([]byte, error) { return marshalBinary([]byte(s)), nil }
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *RedactableBytes) UnmarshalBinary(data []byte) error {
+	b, err := unmarshalBinary(data)
+	if err != nil {
+		return err
+	}
+	*s = RedactableBytes(b)
+	return nil
+}
+
+// StructuredRedactableString is a RedactableString that is marshaled
+// to JSON in the structured form, for the payloads whose consumers
+// prefer the table of spans to the markers. It is unmarshaled from
+// both forms.
+type StructuredRedactableString RedactableString
+
+// MarshalJSON implements json.Marshaler.
+func (s StructuredRedactableString) MarshalJSON() ([]byte, error) {
+	return marshalStructuredJSON([]byte(s))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *StructuredRedactableString) UnmarshalJSON(data []byte) error {
+	b, err := unmarshalJSON(data)
+	if err != nil {
+		return err
+	}
+	*s = StructuredRedactableString(b)
+	return nil
+}
+
+// FromSpans returns the redactable string made of spans, for example
+// as returned by Spans. It returns an error if the text of a span
+// contains markers.
+func FromSpans(spans []Span) (RedactableString, error) {
+	b, err := appendSpans(nil, spans)
+	return RedactableString(b), err
+}
+
+func appendSpans(buf []byte, spans []Span) ([]byte, error) {
+	for _, sp := range spans {
+		if strings.Contains(sp.Text, StartS) || strings.Contains(sp.Text, EndS) ||
+			strings.Contains(sp.Text, HashPrefixS) {
+			return nil, fmt.Errorf("markers in the text of a %s span", sp.Kind)
+		}
+		switch sp.Kind {
+		case SafeSpan:
+			buf = append(buf, sp.Text...)
+		case UnsafeSpan:
+			buf = append(buf, StartS...)
+			buf = append(buf, sp.Text...)
+			buf = append(buf, EndS...)
+		case HashSpan:
+			buf = append(buf, StartS...)
+			buf = append(buf, HashPrefixS...)
+			buf = append(buf, sp.Text...)
+			buf = append(buf, EndS...)
+		case RedactedSpan:
+			buf = append(buf, RedactedS...)
+		default:
+			return nil, fmt.Errorf("invalid span kind: %d", int(sp.Kind))
+		}
+	}
+	return buf, nil
+}
+
+// jsonSpan is a span of the structured JSON form.
+type jsonSpan struct {
+	Kind  string `json:"kind"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// structuredJSONForm is the structured JSON form.
+type structuredJSONForm struct {
+	Text  string     `json:"text"`
+	Spans []jsonSpan `json:"spans"`
+}
+
+func marshalStructuredJSON(data []byte) ([]byte, error) {
+	f := structuredJSONForm{Spans: []jsonSpan{}}
+	var text strings.Builder
+	for _, sp := range spansBytes(data) {
+		start := text.Len()
+		text.WriteString(sp.Text)
+		if sp.Kind != SafeSpan {
+			f.Spans = append(f.Spans, jsonSpan{Kind: sp.Kind.String(), Start: start, End: text.Len()})
+		}
+	}
+	f.Text = text.String()
+	return json.Marshal(f)
+}
+
+func unmarshalJSON(data []byte) ([]byte, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, err
+		}
+		if err := validateBytes(UnicodeDialect, []byte(s)); err != nil {
+			return nil, err
+		}
+		return []byte(s), nil
+	}
+	var f structuredJSONForm
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	spans := make([]Span, 0, 2*len(f.Spans)+1)
+	pos := 0
+	for _, js := range f.Spans {
+		if js.Start < pos || js.End < js.Start || js.End > len(f.Text) {
+			return nil, fmt.Errorf("invalid span [%d,%d) in text of length %d", js.Start, js.End, len(f.Text))
+		}
+		kind, ok := spanKindByName(js.Kind)
+		if !ok || kind == SafeSpan {
+			return nil, fmt.Errorf("invalid span kind: %q", js.Kind)
+		}
+		spans = append(spans,
+			Span{Kind: SafeSpan, Text: f.Text[pos:js.Start]},
+			Span{Kind: kind, Text: f.Text[js.Start:js.End]})
+		pos = js.End
+	}
+	spans = append(spans, Span{Kind: SafeSpan, Text: f.Text[pos:]})
+	return appendSpans(nil, spans)
+}
+
+func spanKindByName(name string) (SpanKind, bool) {
+	for k := SafeSpan; k <= RedactedSpan; k++ {
+		if k.String() == name {
+			return k, true
+		}
+	}
+	return 0, false
+}
+
+// binaryVersion is the version of the binary encoding.
+const binaryVersion = 1
+
+func marshalBinary(data []byte) []byte {
+	spans := spansBytes(data)
+	buf := make([]byte, 0, 1+binary.MaxVarintLen64*(1+len(spans))+len(data))
+	buf = append(buf, binaryVersion)
+	buf = binary.AppendUvarint(buf, uint64(len(spans)))
+	for _, sp := range spans {
+		buf = append(buf, byte(sp.Kind))
+		buf = binary.AppendUvarint(buf, uint64(len(sp.Text)))
+	}
+	for _, sp := range spans {
+		buf = append(buf, sp.Text...)
+	}
+	return buf
+}
+
+var errInvalidBinary = errors.New("invalid binary encoding of redactable string")
+
+func unmarshalBinary(data []byte) ([]byte, error) {
+	if len(data) == 0 || data[0] != binaryVersion {
+		return nil, errInvalidBinary
+	}
+	data = data[1:]
+	n, k := binary.Uvarint(data)
+	// Each span takes at least 2 bytes in the table.
+	if k <= 0 || n > uint64(len(data)-k)/2 {
+		return nil, errInvalidBinary
+	}
+	data = data[k:]
+	spans := make([]Span, n)
+	lengths := make([]uint64, n)
+	for i := range spans {
+		if len(data) == 0 {
+			return nil, errInvalidBinary
+		}
+		spans[i].Kind = SpanKind(data[0])
+		l, k := binary.Uvarint(data[1:])
+		if k <= 0 {
+			return nil, errInvalidBinary
+		}
+		lengths[i] = l
+		data = data[1+k:]
+	}
+	for i := range spans {
+		if lengths[i] > uint64(len(data)) {
+			return nil, errInvalidBinary
+		}
+		spans[i].Text = string(data[:lengths[i]])
+		data = data[lengths[i]:]
+	}
+	if len(data) != 0 {
+		return nil, errInvalidBinary
+	}
+	return appendSpans(make([]byte, 0), spans)
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package markers
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var marshalTestCases = []RedactableString{
+	"",
+	"safe",
+	"user ‹alice› logged in",
+	"‹†alice› ‹×› ‹› ‹a?b›",
+	"‹é›€",
+}
+
+func TestMarshalText(t *testing.T) {
+	for _, s := range marshalTestCases {
+		text, err := s.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var s2 RedactableString
+		if err := s2.UnmarshalText(text); err != nil || s2 != s {
+			t.Errorf("%q: got %q, %v", s, s2, err)
+		}
+		var b RedactableBytes
+		if err := b.UnmarshalText(text); err != nil || string(b) != string(s) {
+			t.Errorf("%q: bytes: got %q, %v", s, b, err)
+		}
+	}
+	var s RedactableString
+	var me *MarkerError
+	if err := s.UnmarshalText([]byte("user ‹alice")); !errors.As(err, &me) {
+		t.Errorf("expected marker error, got %v", err)
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	type payload struct {
+		Msg RedactableString
+		Raw RedactableBytes
+	}
+	type structuredPayload struct {
+		Msg StructuredRedactableString
+	}
+	for _, s := range marshalTestCases {
+		data, err := json.Marshal(payload{Msg: s, Raw: s.ToBytes()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var p payload
+		if err := json.Unmarshal(data, &p); err != nil {
+			t.Fatal(err)
+		}
+		if p.Msg != s || string(p.Raw) != string(s) {
+			t.Errorf("%q: got %q, %q from %s", s, p.Msg, p.Raw, data)
+		}
+
+		// The structured form round-trips through all the types.
+		data, err = json.Marshal(structuredPayload{Msg: StructuredRedactableString(s)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sp structuredPayload
+		if err := json.Unmarshal(data, &sp); err != nil || sp.Msg != StructuredRedactableString(s) {
+			t.Errorf("%q: structured: got %q, %v from %s", s, sp.Msg, err, data)
+		}
+		if err := json.Unmarshal(data, &p); err != nil || p.Msg != s {
+			t.Errorf("%q: structured: got %q, %v from %s", s, p.Msg, err, data)
+		}
+		if sd, err := s.ToBytes().MarshalStructuredJSON(); err != nil || !strings.Contains(string(data), string(sd)) {
+			t.Errorf("%q: expected %s, got %s, %v", s, data, sd, err)
+		}
+	}
+
+	const exp = `{"text":"user alice bob","spans":[{"kind":"unsafe","start":5,"end":10},` +
+		`{"kind":"hash","start":11,"end":14}]}`
+	s := RedactableString("user ‹alice› ‹†bob›")
+	if data, err := s.MarshalStructuredJSON(); err != nil || string(data) != exp {
+		t.Errorf("expected:\n%s\ngot:\n%s, %v", exp, data, err)
+	}
+	if data, err := json.Marshal(StructuredRedactableString(s)); err != nil || string(data) != exp {
+		t.Errorf("expected:\n%s\ngot:\n%s, %v", exp, data, err)
+	}
+	// The default form is a JSON string.
+	if data, err := json.Marshal(s); err != nil || string(data) != `"user ‹alice› ‹†bob›"` {
+		t.Errorf("unexpected JSON string: %s, %v", data, err)
+	}
+
+	for _, input := range []string{
+		`"user ‹alice"`,
+		`{"text":"ab","spans":[{"kind":"unsafe","start":1,"end":3}]}`,
+		`{"text":"ab","spans":[{"kind":"unsafe","start":1,"end":2},{"kind":"unsafe","start":0,"end":1}]}`,
+		`{"text":"ab","spans":[{"kind":"safe","start":0,"end":1}]}`,
+		`{"text":"‹ab","spans":[]}`,
+	} {
+		var s RedactableString
+		if err := json.Unmarshal([]byte(input), &s); err == nil {
+			t.Errorf("%s: expected error, got %q", input, s)
+		}
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	for _, s := range marshalTestCases {
+		data, err := s.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var s2 RedactableString
+		if err := s2.UnmarshalBinary(data); err != nil || s2 != s {
+			t.Errorf("%q: got %q, %v", s, s2, err)
+		}
+		var b RedactableBytes
+		if err := b.UnmarshalBinary(data); err != nil || string(b) != string(s) {
+			t.Errorf("%q: bytes: got %q, %v", s, b, err)
+		}
+	}
+
+	data, _ := RedactableString("a ‹b›").MarshalBinary()
+	if exp := "\x01\x02\x00\x02\x01\x01a b"; string(data) != exp {
+		t.Errorf("expected %q, got %q", exp, data)
+	}
+	for _, input := range []string{
+		"",
+		"\x02\x00",
+		"\x01\x02\x00\x02",
+		"\x01\x01\x00\x05ab",
+		"\x01\x01\x09\x01a",
+		"\x01\x01\x00\x01ab",
+		"\x01\x01\x00\x03‹",
+	} {
+		var s RedactableString
+		if err := s.UnmarshalBinary([]byte(input)); err == nil {
+			t.Errorf("%q: expected error, got %q", input, s)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package markers
+
+import "bytes"
+
+// SpanKind is the kind of a Span.
+type SpanKind int
+
+const (
+	// SafeSpan is text outside of the redaction markers.
+	SafeSpan SpanKind = iota
+	// UnsafeSpan is text enclosed in redaction markers.
+	UnsafeSpan
+	// HashSpan is unsafe text marked for hashing.
+	HashSpan
+	// RedactedSpan is unsafe text that has already been redacted,
+	// i.e. the redacted marker ‹×›.
+	RedactedSpan
+)
+
+// String returns the name of the kind.
+func (k SpanKind) String() string {
+	switch k {
+	case SafeSpan:
+		return "safe"
+	case UnsafeSpan:
+		return "unsafe"
+	case HashSpan:
+		return "hash"
+	case RedactedSpan:
+		return "redacted"
+	}
+	return "unknown"
+}
+
+// Span is a region of a redactable string, for use by the tools that
+// display redactable strings.
+type Span struct {
+	Kind SpanKind
+	// Text is the text of the region, without the markers.
+	Text string
+}
+
+// Spans splits the RedactableString into safe and unsafe regions. The
+// malformed markers are handled as by Repair, so that no unsafe text
+// is reported as safe.
+func (s RedactableString) Spans() []Span {
+	return spansBytes([]byte(s))
+}
+
+// Spans splits the RedactableBytes into safe and unsafe regions. See
+// RedactableString.Spans.
+func (s RedactableBytes) Spans() []Span {
+	return spansBytes([]byte(s))
+}
+
+func spansBytes(data []byte) []Span {
+	data = repairBytes(data)
+	var spans []Span
+	for len(data) > 0 {
+		start := bytes.Index(data, StartBytes)
+		if start == -1 {
+			start = len(data)
+		}
+		if start > 0 {
+			spans = append(spans, Span{Kind: SafeSpan, Text: string(stripMarkersBytes(data[:start], nil))})
+		}
+		if start == len(data) {
+			break
+		}
+		// The repaired data has no unclosed regions.
+		content := data[start+StartLen:]
+		end := bytes.Index(content, EndBytes)
+		content, data = content[:end], content[end+EndLen:]
+		kind := UnsafeSpan
+		switch {
+		case bytes.HasPrefix(content, HashPrefixBytes):
+			kind = HashSpan
+			content = content[len(HashPrefixBytes):]
+		case bytes.Equal(content, RedactedBytes[StartLen:len(RedactedBytes)-EndLen]):
+			kind = RedactedSpan
+		}
+		spans = append(spans, Span{Kind: kind, Text: string(stripMarkersBytes(content, nil))})
+	}
+	return spans
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package markers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSpans(t *testing.T) {
+	testCases := []struct {
+		input string
+		spans []Span
+	}{
+		{"", nil},
+		{"safe", []Span{{SafeSpan, "safe"}}},
+		{"a ‹b› c", []Span{{SafeSpan, "a "}, {UnsafeSpan, "b"}, {SafeSpan, " c"}}},
+		{"‹†alice›‹×›‹›", []Span{{HashSpan, "alice"}, {RedactedSpan, "×"}, {UnsafeSpan, ""}}},
+		{"‹×y› †x", []Span{{UnsafeSpan, "×y"}, {SafeSpan, " x"}}},
+		// Malformed markers are repaired.
+		{"user ‹alice", []Span{{SafeSpan, "user "}, {UnsafeSpan, "alice"}}},
+		{"lice› in", []Span{{UnsafeSpan, "lice"}, {SafeSpan, " in"}}},
+		{"‹a ‹b›", []Span{{UnsafeSpan, "a ?b"}}},
+	}
+	for _, tc := range testCases {
+		if actual := RedactableString(tc.input).Spans(); !reflect.DeepEqual(actual, tc.spans) {
+			t.Errorf("%q: expected %+v, got %+v", tc.input, tc.spans, actual)
+		}
+		if actual := RedactableBytes(tc.input).Spans(); !reflect.DeepEqual(actual, tc.spans) {
+			t.Errorf("%q: bytes: expected %+v, got %+v", tc.input, tc.spans, actual)
+		}
+	}
+}
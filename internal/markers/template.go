@@ -0,0 +1,79 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package markers
+
+import "hash/fnv"
+
+// Template returns the skeleton of the RedactableString, where each
+// unsafe region is replaced by the redacted marker ‹×›, and the unsafe
+// values that were replaced, in order. Each value is returned as a
+// redactable string enclosed in redaction markers, including the hash
+// marker if any, so that the values remain unsafe.
+//
+// The template is that of the normal form of the string (see
+// Canonicalize), so that the strings that are Equal have the same
+// template. For example, the skeleton of "user ‹alice› logged in" is
+// "user ‹×› logged in", and the values are ["‹alice›"].
+func (s RedactableString) Template() (skeleton RedactableString, values []RedactableString) {
+	var buf []byte
+	for _, sp := range spansBytes(canonicalBytes([]byte(s))) {
+		if sp.Kind == SafeSpan {
+			buf = append(buf, sp.Text...)
+			continue
+		}
+		buf = append(buf, RedactedBytes...)
+		// The span texts contain no markers, so this cannot fail.
+		v, _ := appendSpans(nil, []Span{sp})
+		values = append(values, RedactableString(v))
+	}
+	return RedactableString(buf), values
+}
+
+// Template returns the skeleton of the RedactableBytes and the unsafe
+// values. See RedactableString.Template.
+func (s RedactableBytes) Template() (skeleton RedactableBytes, values []RedactableBytes) {
+	sk, vals := RedactableString(s).Template()
+	for _, v := range vals {
+		values = append(values, RedactableBytes(v))
+	}
+	return RedactableBytes(sk), values
+}
+
+// Fingerprint returns a hash of the skeleton of the RedactableString
+// (see Template), to group the messages that differ only by their
+// unsafe values. The hash is stable: it does not depend on the process
+// nor on the hashing configuration, so it can be stored and compared
+// across runs. The unsafe values do not contribute to the hash.
+func (s RedactableString) Fingerprint() uint64 {
+	return fingerprintBytes([]byte(s))
+}
+
+// Fingerprint returns a hash of the skeleton of the RedactableBytes.
+// See RedactableString.Fingerprint.
+func (s RedactableBytes) Fingerprint() uint64 {
+	return fingerprintBytes([]byte(s))
+}
+
+func fingerprintBytes(data []byte) uint64 {
+	h := fnv.New64a()
+	for _, sp := range spansBytes(canonicalBytes(data)) {
+		if sp.Kind == SafeSpan {
+			_, _ = h.Write([]byte(sp.Text))
+		} else {
+			_, _ = h.Write(RedactedBytes)
+		}
+	}
+	return h.Sum64()
+}
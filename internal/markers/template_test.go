@@ -0,0 +1,80 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package markers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTemplate(t *testing.T) {
+	testCases := []struct {
+		input    string
+		skeleton string
+		values   []RedactableString
+	}{
+		{"", "", nil},
+		{"no values", "no values", nil},
+		{"user ‹alice› logged in", "user ‹×› logged in", []RedactableString{"‹alice›"}},
+		{"‹a›‹b› x ‹†c› ‹×›", "‹×› x ‹×› ‹×›", []RedactableString{"‹ab›", "‹†c›", "‹×›"}},
+		{"‹a\nb›", "‹×›\n‹×›", []RedactableString{"‹a›", "‹b›"}},
+		{"user ‹alice", "user ‹×›", []RedactableString{"‹alice›"}},
+	}
+	for _, tc := range testCases {
+		skeleton, values := RedactableString(tc.input).Template()
+		if string(skeleton) != tc.skeleton || !reflect.DeepEqual(values, tc.values) {
+			t.Errorf("%q: expected %q %q, got %q %q", tc.input, tc.skeleton, tc.values, skeleton, values)
+		}
+		bskeleton, bvalues := RedactableBytes(tc.input).Template()
+		if string(bskeleton) != tc.skeleton || len(bvalues) != len(tc.values) {
+			t.Errorf("%q: bytes: expected %q %q, got %q %q", tc.input, tc.skeleton, tc.values, bskeleton, bvalues)
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	f := RedactableString("user ‹alice› logged in").Fingerprint()
+	for _, s := range []string{
+		"user ‹bob› logged in",
+		"user ‹†carol› logged in",
+		"user ‹b›‹o›‹b› logged in",
+	} {
+		if actual := RedactableString(s).Fingerprint(); actual != f {
+			t.Errorf("%q: expected %x, got %x", s, f, actual)
+		}
+		if actual := RedactableBytes(s).Fingerprint(); actual != f {
+			t.Errorf("%q: bytes: expected %x, got %x", s, f, actual)
+		}
+	}
+	for _, s := range []string{
+		"user ‹alice› logged out",
+		"user alice logged in",
+		"user ‹alice› ‹x› logged in",
+	} {
+		if actual := RedactableString(s).Fingerprint(); actual == f {
+			t.Errorf("%q: expected a different fingerprint", s)
+		}
+	}
+	// The fingerprint does not depend on the hashing configuration.
+	EnableHashing([]byte("salt"))
+	defer DisableHashing()
+	if actual := RedactableString("user ‹†dave› logged in").Fingerprint(); actual != f {
+		t.Errorf("expected %x with hashing enabled, got %x", f, actual)
+	}
+	// The fingerprint is stable across releases.
+	if f != 0xf894be6c696d391e {
+		t.Errorf("unexpected fingerprint %#x", f)
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package markers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MarkerProblemKind is the kind of a malformed marker.
+type MarkerProblemKind int
+
+const (
+	// UnclosedStartMarker is a start marker without a matching end
+	// marker, for example in a truncated string.
+	UnclosedStartMarker MarkerProblemKind = iota + 1
+	// UnmatchedEndMarker is an end marker outside of an unsafe region.
+	UnmatchedEndMarker
+	// NestedStartMarker is a start marker inside an unsafe region.
+	NestedStartMarker
+)
+
+// String implements fmt.Stringer.
+func (k MarkerProblemKind) String() string {
+	switch k {
+	case UnclosedStartMarker:
+		return "start marker without end marker"
+	case UnmatchedEndMarker:
+		return "end marker without start marker"
+	case NestedStartMarker:
+		return "start marker inside unsafe region"
+	default:
+		return fmt.Sprintf("MarkerProblemKind(%d)", int(k))
+	}
+}
+
+// MarkerProblem is a malformed marker in a redactable string.
+type MarkerProblem struct {
+	// Pos is the byte offset of the offending marker.
+	Pos  int
+	Kind MarkerProblemKind
+}
+
+// MarkerError is returned by Validate when a redactable string
+// contains malformed markers.
+type MarkerError struct {
+	// Problems lists the malformed markers, ordered by position.
+	Problems []MarkerProblem
+}
+
+// Error implements the error interface.
+func (e *MarkerError) Error() string {
+	var b strings.Builder
+	b.WriteString("malformed redactable string: ")
+	for i, p := range e.Problems {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s at byte %d", p.Kind, p.Pos)
+	}
+	return b.String()
+}
+
+// Validate checks that the redaction markers in the string are
+// balanced. It returns a *MarkerError listing the malformed markers
+// otherwise.
+//
+// A string with malformed markers cannot be redacted reliably: for
+// example, Redact preserves the text following an unclosed start
+// marker. Use Repair to make such a string safe to redact.
+func (s RedactableString) Validate() error {
+	if strings.IndexByte(string(s), StartBytes[0]) == -1 {
+		return nil
+	}
+	return validateBytes(UnicodeDialect, []byte(s))
+}
+
+// Repair returns a copy of the string where the malformed markers
+// have been fixed so that the result is well-formed. The repair fails
+// closed, by extending the unsafe regions rather than shrinking them:
+//
+//   - the text following an unclosed start marker is considered
+//     unsafe, and an end marker is added at the end.
+//   - the text preceding an unmatched end marker, up to the previous
+//     unsafe region or the beginning of the string, is considered
+//     unsafe, and a start marker is added before it.
+//   - a start marker inside an unsafe region is escaped, so that the
+//     region extends to the next end marker.
+//
+// A well-formed string is returned unchanged.
+func (s RedactableString) Repair() RedactableString {
+	if strings.IndexByte(string(s), StartBytes[0]) == -1 {
+		return s
+	}
+	return RedactableString(repairBytes([]byte(s)))
+}
+
+// Validate checks that the redaction markers are balanced. See
+// RedactableString.Validate for details.
+func (s RedactableBytes) Validate() error {
+	return validateBytes(UnicodeDialect, []byte(s))
+}
+
+// Repair returns a copy of the bytes where the malformed markers have
+// been fixed. See RedactableString.Repair for details. The result never
+// aliases s, even when s is well-formed.
+func (s RedactableBytes) Repair() RedactableBytes {
+	if validateBytes(UnicodeDialect, s) == nil {
+		return append(RedactableBytes(nil), s...)
+	}
+	return RedactableBytes(repairBytes(s))
+}
+
+// markerEvent is the role of a marker found by scanMarkers.
+type markerEvent int
+
+const (
+	openRegion markerEvent = iota
+	closeRegion
+	nestedStart
+	unmatchedEnd
+)
+
+// scanMarkers calls fn for each start and end marker in data, a
+// redactable string in dialect d, in order. It returns the position of
+// the start marker of the region left open at the end of data, or -1
+// if there is none.
+func scanMarkers(d *Dialect, data []byte, fn func(pos int, ev markerEvent)) (openAt int) {
+	openAt = -1
+	for i := 0; i < len(data); {
+		tok, n, _ := d.scan(data[i:])
+		switch tok {
+		case tokStart:
+			if openAt != -1 {
+				fn(i, nestedStart)
+			} else {
+				openAt = i
+				fn(i, openRegion)
+			}
+		case tokEnd:
+			if openAt == -1 {
+				fn(i, unmatchedEnd)
+			} else {
+				openAt = -1
+				fn(i, closeRegion)
+			}
+		}
+		i += n
+	}
+	return openAt
+}
+
+func validateBytes(d *Dialect, data []byte) error {
+	var problems []MarkerProblem
+	openAt := scanMarkers(d, data, func(pos int, ev markerEvent) {
+		switch ev {
+		case nestedStart:
+			problems = append(problems, MarkerProblem{Pos: pos, Kind: NestedStartMarker})
+		case unmatchedEnd:
+			problems = append(problems, MarkerProblem{Pos: pos, Kind: UnmatchedEndMarker})
+		}
+	})
+	if openAt != -1 {
+		problems = append(problems, MarkerProblem{Pos: openAt, Kind: UnclosedStartMarker})
+		sort.SliceStable(problems, func(i, j int) bool { return problems[i].Pos < problems[j].Pos })
+	}
+	if problems == nil {
+		return nil
+	}
+	return &MarkerError{Problems: problems}
+}
+
+func repairBytes(data []byte) []byte {
+	if validateBytes(UnicodeDialect, data) == nil {
+		return data
+	}
+	buf := make([]byte, 0, len(data)+StartLen+EndLen)
+	// pos is the position in data up to which the input has been
+	// copied to buf; boundary is the position in buf where the current
+	// safe stretch of text starts.
+	pos, boundary := 0, 0
+	openAt := scanMarkers(UnicodeDialect, data, func(i int, ev markerEvent) {
+		buf = append(buf, data[pos:i]...)
+		pos = i
+		switch ev {
+		case nestedStart:
+			buf = append(buf, EscapeMarkBytes...)
+			pos += StartLen
+		case unmatchedEnd:
+			// Make the text since the last boundary unsafe.
+			buf = append(buf, StartBytes...)
+			copy(buf[boundary+StartLen:], buf[boundary:])
+			copy(buf[boundary:], StartBytes)
+			buf = append(buf, EndBytes...)
+			pos += EndLen
+			boundary = len(buf)
+		case closeRegion:
+			buf = append(buf, EndBytes...)
+			pos += EndLen
+			boundary = len(buf)
+		}
+	})
+	buf = append(buf, data[pos:]...)
+	if openAt != -1 {
+		buf = append(buf, EndBytes...)
+	}
+	return buf
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package markers
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidateRepair(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		problems []MarkerProblem
+		repaired string
+		redacted string
+	}{
+		{
+			name:     "empty",
+			input:    "",
+			repaired: "",
+			redacted: "",
+		},
+		{
+			name:     "well-formed",
+			input:    "a ‹b› c ‹†d› ‹×› €",
+			repaired: "a ‹b› c ‹†d› ‹×› €",
+			redacted: "a ‹×› c ‹×› ‹×› €",
+		},
+		{
+			name:     "unclosed start marker",
+			input:    "user ‹alice logged in",
+			problems: []MarkerProblem{{5, UnclosedStartMarker}},
+			repaired: "user ‹alice logged in›",
+			redacted: "user ‹×›",
+		},
+		{
+			name:     "unclosed after well-formed region",
+			input:    "‹a› b ‹c",
+			problems: []MarkerProblem{{10, UnclosedStartMarker}},
+			repaired: "‹a› b ‹c›",
+			redacted: "‹×› b ‹×›",
+		},
+		{
+			name:     "unmatched end marker",
+			input:    "lice› logged in",
+			problems: []MarkerProblem{{4, UnmatchedEndMarker}},
+			repaired: "‹lice› logged in",
+			redacted: "‹×› logged in",
+		},
+		{
+			name:     "unmatched end marker after region",
+			input:    "‹a› b› c",
+			problems: []MarkerProblem{{9, UnmatchedEndMarker}},
+			repaired: "‹a›‹ b› c",
+			redacted: "‹×›‹×› c",
+		},
+		{
+			name:     "nested start marker",
+			input:    "‹a ‹b› c",
+			problems: []MarkerProblem{{5, NestedStartMarker}},
+			repaired: "‹a ?b› c",
+			redacted: "‹×› c",
+		},
+		{
+			name:  "several problems",
+			input: "x› ‹y ‹z",
+			problems: []MarkerProblem{
+				{1, UnmatchedEndMarker},
+				{5, UnclosedStartMarker},
+				{10, NestedStartMarker},
+			},
+			repaired: "‹x› ‹y ?z›",
+			redacted: "‹×› ‹×›",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := RedactableString(tc.input)
+			err := s.Validate()
+			if tc.problems == nil {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+			} else {
+				var me *MarkerError
+				if !errors.As(err, &me) {
+					t.Fatalf("expected MarkerError, got %v", err)
+				}
+				if !reflect.DeepEqual(me.Problems, tc.problems) {
+					t.Errorf("expected %+v, got %+v", tc.problems, me.Problems)
+				}
+			}
+			if err := RedactableBytes(tc.input).Validate(); (err == nil) != (tc.problems == nil) {
+				t.Errorf("bytes: unexpected error %v", err)
+			}
+
+			repaired := s.Repair()
+			if string(repaired) != tc.repaired {
+				t.Errorf("expected repair %q, got %q", tc.repaired, repaired)
+			}
+			if err := repaired.Validate(); err != nil {
+				t.Errorf("repaired string is malformed: %v", err)
+			}
+			in := RedactableBytes(tc.input)
+			b := in.Repair()
+			if string(b) != tc.repaired {
+				t.Errorf("bytes: expected repair %q, got %q", tc.repaired, b)
+			}
+			if len(b) > 0 && &b[0] == &in[0] {
+				t.Errorf("bytes: repair aliases the input")
+			}
+			if actual := repaired.Redact(); string(actual) != tc.redacted {
+				t.Errorf("expected redacted %q, got %q", tc.redacted, actual)
+			}
+		})
+	}
+}
+
+func TestMarkerError(t *testing.T) {
+	err := RedactableString("a› ‹b").Validate()
+	const exp = "malformed redactable string: end marker without start marker at byte 1, " +
+		"start marker without end marker at byte 5"
+	if err == nil || err.Error() != exp {
+		t.Errorf("expected %q, got %v", exp, err)
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package markers
+
+import "unicode/utf8"
+
+// VisibleWidth returns the number of characters in s, a redactable
+// string, excluding the redaction markers. This is the width of s
+// once displayed with its markers stripped, and is used to align
+// redactable strings in columns.
+func VisibleWidth(s []byte) int {
+	n := utf8.RuneCount(s)
+	for i := 0; i < len(s); i++ {
+		if _, ok := isUnicodeMarker(s[i:]); ok {
+			n--
+			i += markerLen - 1
+		}
+	}
+	return n
+}
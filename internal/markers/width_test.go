@@ -0,0 +1,38 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package markers
+
+import "testing"
+
+func TestVisibleWidth(t *testing.T) {
+	testCases := []struct {
+		input string
+		width int
+	}{
+		{"", 0},
+		{"hello", 5},
+		{"‹alice›", 5},
+		{"a ‹×› b", 5},
+		{"‹†bob› €", 5},
+		{"?a?", 3},
+		{"‹‹›", 0},
+		{"\xe2\x80", 2},
+	}
+	for _, tc := range testCases {
+		if actual := VisibleWidth([]byte(tc.input)); actual != tc.width {
+			t.Errorf("%q: expected %d, got %d", tc.input, tc.width, actual)
+		}
+	}
+}
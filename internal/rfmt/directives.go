@@ -0,0 +1,135 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rfmt
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ArgDirectives returns, for each argument, the directive of the format
+// string that prints it, for example "%-5s", so that the argument can
+// be printed on its own with the same result. The explicit argument
+// indexes are resolved, and the widths and precisions given by
+// arguments ('*') are replaced by their values. The arguments that are
+// used as widths or precisions, or that are not referenced by the
+// format, get the directive "%v". The verb %w is replaced by %v.
+func ArgDirectives(format string, args []interface{}) []string {
+	dirs := make([]string, len(args))
+	for i := range dirs {
+		dirs[i] = "%v"
+	}
+	d := directiveParser{format: format, args: args}
+	for d.i < len(format) {
+		if format[d.i] != '%' {
+			d.i++
+			continue
+		}
+		d.i++
+		d.good = true
+		flagStart := d.i
+		for d.i < len(format) && strings.IndexByte("+-# 0", format[d.i]) != -1 {
+			d.i++
+		}
+		flags := format[flagStart:d.i]
+
+		// Width.
+		d.argIndex()
+		width, ok := d.number()
+		if ok && width != "" && width[0] == '-' {
+			// A negative width means left justification.
+			width = width[1:]
+			if strings.IndexByte(flags, '-') == -1 {
+				flags += "-"
+			}
+		}
+		// Precision.
+		prec := ""
+		if d.i < len(format) && format[d.i] == '.' {
+			d.i++
+			d.argIndex()
+			p, ok := d.number()
+			// A negative precision means no precision.
+			if !ok || p == "" || p[0] != '-' {
+				prec = "." + p
+			}
+		}
+		d.argIndex()
+		if d.i >= len(format) {
+			break
+		}
+
+		verb, size := utf8.DecodeRuneInString(format[d.i:])
+		d.i += size
+		switch {
+		case verb == '%':
+			continue
+		case !d.good:
+			// Bad argument index: nothing is printed.
+			continue
+		case verb == 'w':
+			verb = 'v'
+		}
+		if d.argNum < len(args) {
+			dirs[d.argNum] = "%" + flags + width + prec + string(verb)
+		}
+		d.argNum++
+	}
+	return dirs
+}
+
+// directiveParser is the state of ArgDirectives. It follows the
+// parsing of doPrintf.
+type directiveParser struct {
+	format string
+	args   []interface{}
+	i      int
+	argNum int
+	good   bool
+}
+
+// argIndex parses an explicit argument index [n], if any.
+func (d *directiveParser) argIndex() {
+	if d.i >= len(d.format) || d.format[d.i] != '[' {
+		return
+	}
+	index, wid, ok := parseArgNumber(d.format[d.i:])
+	d.i += wid
+	if ok && 0 <= index && index < len(d.args) {
+		d.argNum = index
+	} else {
+		d.good = false
+	}
+}
+
+// number parses a width or precision, given either by digits or by an
+// argument ('*'). The boolean is true in the latter case.
+func (d *directiveParser) number() (string, bool) {
+	if d.i < len(d.format) && d.format[d.i] == '*' {
+		d.i++
+		n, isInt, newArgNum := intFromArg(d.args, d.argNum)
+		d.argNum = newArgNum
+		if !isInt {
+			return "", true
+		}
+		return strconv.Itoa(n), true
+	}
+	start := d.i
+	for d.i < len(d.format) && '0' <= d.format[d.i] && d.format[d.i] <= '9' {
+		d.i++
+	}
+	return d.format[start:d.i], false
+}
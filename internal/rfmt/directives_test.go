@@ -0,0 +1,46 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rfmt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestArgDirectives(t *testing.T) {
+	testCases := []struct {
+		format   string
+		args     []interface{}
+		expected []string
+	}{
+		{"", nil, []string{}},
+		{"hello %s", []interface{}{"a"}, []string{"%s"}},
+		{"%d%% %-5s %+.2f %#x", []interface{}{1, "a", 2.0, 3}, []string{"%d", "%-5s", "%+.2f", "%#x"}},
+		{"%[2]s %[1]q %s", []interface{}{"a", "b", "c"}, []string{"%q", "%s", "%v"}},
+		{"%*d %.*f", []interface{}{5, 1, -2, 3.0}, []string{"%v", "%5d", "%v", "%f"}},
+		{"%-*d", []interface{}{-4, 1}, []string{"%v", "%-4d"}},
+		{"%w: %v", []interface{}{nil, 1}, []string{"%v", "%v"}},
+		{"%s", []interface{}{"a", "extra"}, []string{"%s", "%v"}},
+		{"%s %s", []interface{}{"a"}, []string{"%s"}},
+		{"%[x]d %d", []interface{}{1}, []string{"%d"}},
+		{"%[3]d %d", []interface{}{1, 2}, []string{"%d", "%v"}},
+		{"trailing %", []interface{}{1}, []string{"%v"}},
+	}
+	for _, tc := range testCases {
+		if actual := ArgDirectives(tc.format, tc.args); !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%q: expected %q, got %q", tc.format, tc.expected, actual)
+		}
+	}
+}
@@ -128,18 +128,118 @@ func (p *pp) handleSpecialValues(
 
 	case redactableStringType:
 		handled = true
-		defer p.startPreRedactable().restore()
-		p.buf.WriteString(value.String())
+		p.printRedactableString(value.String())
 
 	case redactableBytesType:
 		handled = true
-		defer p.startPreRedactable().restore()
-		p.buf.Write(value.Bytes())
+		p.printRedactableBytes(value.Bytes())
+
+	default:
+		// CUSTOM: secrets are never printed, not even as unsafe data.
+		if t.Implements(secretValueType) {
+			handled = true
+			var arg interface{}
+			if value.CanInterface() {
+				arg = value.Interface()
+			}
+			p.printSecret(arg, verb)
+		}
 	}
 
 	return handled
 }
 
+// printSecret prints the placeholder of a secret value, regardless of
+// the safe and unsafe overrides. If arg is a SafeFormatter, the
+// placeholder is that printed by its SafeFormat method. Otherwise, or
+// if the value is not accessible, arg is nil and the placeholder is
+// the redacted marker.
+func (p *pp) printSecret(arg interface{}, verb rune) {
+	defer restorer{p, p.buf.GetMode(), p.override}.restore()
+	p.override = noOverride
+	if f, ok := arg.(i.SafeFormatter); ok {
+		defer p.catchPanic(arg, verb, "SafeFormat")
+		p.safeFormat(f, verb)
+		return
+	}
+	p.printRedactableBytes(m.RedactedMarker())
+}
+
+func (p *pp) printRedactableString(s string) {
+	if p.fmt.widPresent {
+		p.padRedactable([]byte(s))
+		return
+	}
+	defer p.startPreRedactable().restore()
+	p.buf.WriteString(s)
+}
+
+func (p *pp) printRedactableBytes(s []byte) {
+	if p.fmt.widPresent {
+		p.padRedactable(s)
+		return
+	}
+	defer p.startPreRedactable().restore()
+	p.buf.Write(s)
+}
+
+// safeFormat calls the SafeFormat method of v. When a width is
+// specified, the output is padded according to its visible width,
+// i.e. excluding the redaction markers.
+func (p *pp) safeFormat(v i.SafeFormatter, verb rune) {
+	if !p.fmt.widPresent {
+		v.SafeFormat(p, verb)
+		return
+	}
+	// The output is collected in a separate buffer to compute its
+	// width. The width is not applied to the values printed by
+	// SafeFormat itself.
+	prevBuf := p.buf
+	p.buf = buffer{}
+	p.buf.SetMode(prevBuf.GetMode())
+	p.fmt.widPresent = false
+	defer func() {
+		s := p.buf.TakeRedactableBytes()
+		p.buf = prevBuf
+		p.fmt.widPresent = true
+		p.padRedactable(s)
+	}()
+	v.SafeFormat(p, verb)
+}
+
+// padRedactable writes the redactable string s, padded on the left or
+// on the right according to the width flags. The width is computed
+// from the visible characters of s, so the padding is the same as that
+// of the string with the markers stripped. The padding itself is safe.
+func (p *pp) padRedactable(s []byte) {
+	if p.override == overrideUnsafe {
+		// The markers are escaped and thus visible.
+		defer p.startPreRedactable().restore()
+		p.fmt.pad(s)
+		return
+	}
+	width := p.fmt.wid - m.VisibleWidth(s)
+	if !p.fmt.minus {
+		p.writeSafePadding(width)
+	}
+	func() {
+		defer p.startPreRedactable().restore()
+		p.buf.Write(s)
+	}()
+	if p.fmt.minus {
+		p.writeSafePadding(width)
+	}
+}
+
+func (p *pp) writeSafePadding(n int) {
+	prevMode := p.buf.GetMode()
+	if prevMode != b.SafeRaw {
+		p.buf.SetMode(b.SafeEscaped)
+	}
+	p.fmt.writePadding(n)
+	p.buf.SetMode(prevMode)
+}
+
 // Sprintfn produces a RedactableString using the provided
 // SafeFormat-alike function.
 func Sprintfn(printer func(w i.SafePrinter)) m.RedactableString {
@@ -150,6 +250,38 @@ func Sprintfn(printer func(w i.SafePrinter)) m.RedactableString {
 	return s
 }
 
+// Append formats using the default formats for its operands, appends
+// the result to dst and returns the extended buffer. Spaces are added
+// between operands when neither is a string.
+func Append(dst []byte, a ...interface{}) m.RedactableBytes {
+	p := newPrinter()
+	p.doPrint(a)
+	dst = append(dst, p.buf.RedactableBytes()...)
+	p.free()
+	return m.RedactableBytes(dst)
+}
+
+// Appendf formats according to a format specifier, appends the result
+// to dst and returns the extended buffer.
+func Appendf(dst []byte, format string, a ...interface{}) m.RedactableBytes {
+	p := newPrinter()
+	p.doPrintf(format, a)
+	dst = append(dst, p.buf.RedactableBytes()...)
+	p.free()
+	return m.RedactableBytes(dst)
+}
+
+// Appendln formats using the default formats for its operands, appends
+// the result to dst and returns the extended buffer. Spaces are always
+// added between operands and a newline is appended.
+func Appendln(dst []byte, a ...interface{}) m.RedactableBytes {
+	p := newPrinter()
+	p.doPrintln(a)
+	dst = append(dst, p.buf.RedactableBytes()...)
+	p.free()
+	return m.RedactableBytes(dst)
+}
+
 // HelperForErrorf is a helper to implement a redaction-aware
 // fmt.Errorf-compatible function in a different package. It formats
 // the string according to the given format and arguments in the same
@@ -188,4 +320,5 @@ var (
 	safeWrapperType      = reflect.TypeOf(rwrap.SafeWrapper{})
 	redactableStringType = reflect.TypeOf(m.RedactableString(""))
 	redactableBytesType  = reflect.TypeOf(m.RedactableBytes{})
+	secretValueType      = reflect.TypeOf((*i.SecretValue)(nil)).Elem()
 )
@@ -623,11 +623,18 @@ func (p *pp) handleMethods(verb rune) (handled bool) {
 	}
 
 	if p.override != overrideUnsafe {
+		// CUSTOM: safe formatters registered for types defined elsewhere.
+		if fn := safeFormatterRegistry[reflect.TypeOf(p.arg)]; fn != nil {
+			handled = true
+			defer p.catchPanic(p.arg, verb, "SafeFormat")
+			p.safeFormat(registeredFormatter{p.arg, fn}, verb)
+			return
+		}
 		switch v := p.arg.(type) {
 		case i.SafeFormatter:
 			handled = true
 			defer p.catchPanic(p.arg, verb, "SafeFormat")
-			v.SafeFormat(p, verb)
+			p.safeFormat(v, verb)
 			return
 
 		case i.SafeMessager:
@@ -738,6 +745,12 @@ func (p *pp) printArg(arg interface{}, verb rune) {
 		return
 	}
 
+	// CUSTOM: secrets are never printed, not even as unsafe data.
+	if _, ok := arg.(i.SecretValue); ok {
+		p.printSecret(arg, verb)
+		return
+	}
+
 	// Some types can be done without reflection.
 	switch f := arg.(type) {
 	case bool:
@@ -803,12 +816,12 @@ func (p *pp) printArg(arg interface{}, verb rune) {
 		}
 		p.printValue(f, verb, 0)
 	case m.RedactableString:
-		defer p.startPreRedactable().restore()
-		p.buf.WriteString(string(f))
+		// CUSTOM: padded according to the visible width.
+		p.printRedactableString(string(f))
 		return
 	case m.RedactableBytes:
-		defer p.startPreRedactable().restore()
-		p.buf.Write([]byte(f))
+		// CUSTOM: padded according to the visible width.
+		p.printRedactableBytes([]byte(f))
 		return
 	default:
 		// If the type is not simple, it might have methods.
@@ -34,6 +34,34 @@ func isSafeValue(a interface{}) bool {
 	return safeTypeRegistry[reflect.TypeOf(a)]
 }
 
+// IsSafeType returns true if t was registered with RegisterSafeType.
+func IsSafeType(t reflect.Type) bool {
+	return safeTypeRegistry[t]
+}
+
+// RegisterSafeFormatter registers a function to format the values of
+// the type t during the production of redactable strings, as if the
+// type implemented SafeFormatter. This is meant for the types defined
+// in other packages, which can only be marked safe as a whole with
+// RegisterSafeType.
+func RegisterSafeFormatter(t reflect.Type, fn func(v interface{}, p i.SafePrinter, verb rune)) {
+	safeFormatterRegistry[t] = fn
+}
+
+// safeFormatterRegistry registers the formatting functions of
+// RegisterSafeFormatter.
+var safeFormatterRegistry = map[reflect.Type]func(v interface{}, p i.SafePrinter, verb rune){}
+
+// registeredFormatter adapts a value and its registered formatting
+// function to SafeFormatter.
+type registeredFormatter struct {
+	v  interface{}
+	fn func(v interface{}, p i.SafePrinter, verb rune)
+}
+
+// SafeFormat implements SafeFormatter.
+func (r registeredFormatter) SafeFormat(p i.SafePrinter, verb rune) { r.fn(r.v, p, verb) }
+
 // redactErrorFn can be injected from an error library
 // to render error objects safely.
 var redactErrorFn func(err error, p i.SafePrinter, verb rune)
@@ -18,6 +18,7 @@ import (
 	"reflect"
 	"testing"
 
+	i "github.com/cockroachdb/redact/interfaces"
 	w "github.com/cockroachdb/redact/internal/redact"
 )
 
@@ -66,3 +67,35 @@ func TestCustomSafeTypes(t *testing.T) {
 		t.Errorf("expected %q, got %q", expected3, actual)
 	}
 }
+
+func TestCustomSafeFormatters(t *testing.T) {
+	defer func(prev map[reflect.Type]func(interface{}, i.SafePrinter, rune)) {
+		safeFormatterRegistry = prev
+	}(safeFormatterRegistry)
+
+	type pair struct {
+		name   string
+		secret string
+	}
+	RegisterSafeFormatter(reflect.TypeOf(pair{}), func(v interface{}, p i.SafePrinter, _ rune) {
+		x := v.(pair)
+		p.Printf("%s=%s", i.SafeString(x.name), x.secret)
+	})
+
+	x := pair{"user", "alice"}
+	actual := Sprint(x, &x, []pair{x}, reflect.ValueOf(x))
+	const expected = `user=‹alice› &user=‹alice› [user=‹alice›] user=‹alice›`
+	if actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+
+	// The width applies to the whole rendering.
+	if actual := Sprintf("[%-12v]", x); actual != `[user=‹alice›  ]` {
+		t.Errorf("unexpected padding: %q", actual)
+	}
+
+	// Unsafe overrides the registration.
+	if actual := Sprint(w.Unsafe(x)); actual != `‹{user alice}›` {
+		t.Errorf("expected unsafe, got %q", actual)
+	}
+}
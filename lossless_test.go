@@ -0,0 +1,103 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package redact
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLosslessEscaping(t *testing.T) {
+	values := []interface{}{
+		"plain",
+		"a ‹b› c",
+		"†hash",
+		"?{41}",
+		"trailing ?",
+		"?",
+		"{",
+		"multi\nline ‹x›\n",
+		"invalid \xe2\x80",
+		"\xff",
+		[]byte("bytes ‹›"),
+		'‹',
+		Safe("safe ‹s› ?{"),
+		SafeString("safe?"),
+	}
+
+	testCases := []struct {
+		format string
+		args   []interface{}
+	}{
+		{"%v", values},
+		{"%s%s", []interface{}{"a?", "{41}"}},
+		{"%s%s", []interface{}{Safe("a?"), Safe("{41}")}},
+		{"x?%s", []interface{}{Safe("{41}")}},
+		{"%c%c%c", []interface{}{'\xe2', '\x80', '\xb9'}},
+		{"%q", []interface{}{"‹q›"}},
+		{"‹fmt› ?{", nil},
+	}
+
+	for _, tc := range testCases {
+		argLists := [][]interface{}{tc.args}
+		if tc.format == "%v" {
+			argLists = nil
+			for _, a := range tc.args {
+				argLists = append(argLists, []interface{}{a})
+			}
+		}
+		for _, args := range argLists {
+			exp := fmt.Sprintf(tc.format, args...)
+
+			lossy := Sprintf(tc.format, args...)
+			EnableLosslessEscaping()
+			lossless := Sprintf(tc.format, args...)
+			DisableLosslessEscaping()
+
+			if actual := lossless.StripMarkersExact(); actual != exp {
+				t.Errorf("%q %v: expected %q, got %q (from %q)", tc.format, args, exp, actual, lossless)
+			}
+			if err := lossless.Validate(); err != nil {
+				t.Errorf("%q %v: %v", tc.format, args, err)
+			}
+			// The same unsafe regions are redacted.
+			lossyRedacted, losslessRedacted := string(lossy.Redact()), string(lossless.Redact())
+			if strings.Count(lossyRedacted, string(RedactedMarker())) !=
+				strings.Count(losslessRedacted, string(RedactedMarker())) {
+				t.Errorf("%q %v: redaction differs: %q vs %q", tc.format, args, lossyRedacted, losslessRedacted)
+			}
+		}
+	}
+}
+
+func TestLosslessEscapingRedact(t *testing.T) {
+	EnableLosslessEscaping()
+	defer DisableLosslessEscaping()
+
+	s := Sprintf("user %s said %s", "‹alice›", Safe("?{hi}"))
+	if exp := RedactableString("user ‹?{e280b9}alice?{e280ba}› said ?{3f}{hi}"); s != exp {
+		t.Errorf("expected %q, got %q", exp, s)
+	}
+	if exp := RedactableString("user ‹×› said ?{3f}{hi}"); s.Redact() != exp {
+		t.Errorf("expected %q, got %q", exp, s.Redact())
+	}
+	if exp := "user ‹alice› said ?{hi}"; s.StripMarkersExact() != exp {
+		t.Errorf("expected %q, got %q", exp, s.StripMarkersExact())
+	}
+	if exp := "user ?{e280b9}alice?{e280ba} said ?{3f}{hi}"; s.StripMarkers() != exp {
+		t.Errorf("expected %q, got %q", exp, s.StripMarkers())
+	}
+}
@@ -41,6 +41,32 @@ func Sprintf(format string, args ...interface{}) RedactableString {
 	return rfmt.Sprintf(format, args...)
 }
 
+// Sprintln is like Sprint but always adds spaces between the
+// arguments and a newline after the last argument.
+func Sprintln(args ...interface{}) RedactableString {
+	return rfmt.Sprintln(args...)
+}
+
+// Append is like Sprint but appends the redactable string to dst and
+// returns the extended buffer. The buffer can be reused across calls
+// to avoid allocations. dst should contain a redactable string, so
+// that the result is one too.
+func Append(dst []byte, args ...interface{}) RedactableBytes {
+	return rfmt.Append(dst, args...)
+}
+
+// Appendf is like Sprintf but appends the redactable string to dst
+// and returns the extended buffer. See Append.
+func Appendf(dst []byte, format string, args ...interface{}) RedactableBytes {
+	return rfmt.Appendf(dst, format, args...)
+}
+
+// Appendln is like Sprintln but appends the redactable string to dst
+// and returns the extended buffer. See Append.
+func Appendln(dst []byte, args ...interface{}) RedactableBytes {
+	return rfmt.Appendln(dst, args...)
+}
+
 // HelperForErrorf is a helper to implement a redaction-aware
 // fmt.Errorf-compatible function in a different package. It formats
 // the string according to the given format and arguments in the same
@@ -85,3 +111,15 @@ func Fprint(w io.Writer, args ...interface{}) (n int, err error) {
 func Fprintf(w io.Writer, format string, args ...interface{}) (n int, err error) {
 	return rfmt.Fprintf(w, format, args...)
 }
+
+// Fprintln is like Sprintln but outputs the redactable string to the
+// provided Writer.
+func Fprintln(w io.Writer, args ...interface{}) (n int, err error) {
+	return rfmt.Fprintln(w, args...)
+}
+
+// Printf is like Sprintf but outputs the redactable string to the
+// standard output.
+func Printf(format string, args ...interface{}) (n int, err error) {
+	return rfmt.Printf(format, args...)
+}
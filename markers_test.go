@@ -149,6 +149,17 @@ func TestPrinter(t *testing.T) {
 		{func(w p) { w.Print("ab ", Sprint(12, Safe(34))) }, "‹ab ›‹12› 34"},
 		{func(w p) { w.Printf("ab %q", Sprint(12, Safe(34))) }, "ab ‹12› 34"},
 		{func(w p) { w.Printf("ab %d", Sprint(12, Safe(34))) }, "ab ‹12› 34"},
+		// Except for the width, which is computed from the visible
+		// characters. The padding is safe.
+		{func(w p) { w.Printf("[%-8s]", Sprint(12, Safe(34))) }, "[‹12› 34   ]"},
+		{func(w p) { w.Printf("[%8s]", RedactableBytes("‹ab›")) }, "[      ‹ab›]"},
+		{func(w p) { w.Printf("[%4v]", Unsafe(RedactableString("‹a›"))) }, "[‹ ?a?›]"},
+		{func(w p) {
+			w.Printf("[%-8v]", compose{fn: func(w p) { w.Printf("a %s", "bc") }})
+		}, "[a ‹bc›    ]"},
+		{func(w p) {
+			w.Printf("[%6v]", compose{fn: func(w p) { w.SafeInt(12) }})
+		}, "[    12]"},
 		// Nil untyped or interface-typed objects get formatted as safe.
 		{func(w p) { w.Printf("ab %v", nil) }, "ab <nil>"},
 		{func(w p) { w.Printf("ab %v", error(nil)) }, "ab <nil>"},
@@ -739,3 +750,28 @@ func makeMixedRedactableBytes() mixedRedactableBytes {
 		Rp: &r,
 	}
 }
+
+func TestAppend(t *testing.T) {
+	buf := Appendf([]byte("a ‹b› "), "%s %d", "c", Safe(1))
+	buf = Append(buf, " ", "d", Safe("e"))
+	buf = Appendln(buf, "f", Safe("g"))
+	if exp := "a ‹b› ‹c› 1‹ d›e‹f› g\n"; string(buf) != exp {
+		t.Errorf("expected %q, got %q", exp, buf)
+	}
+	if s := Sprintln("f", Safe("g")); s != "‹f› g\n" {
+		t.Errorf("unexpected Sprintln result %q", s)
+	}
+	var sb strings.Builder
+	if _, err := Fprintln(&sb, "f", Safe("g")); err != nil || sb.String() != "‹f› g\n" {
+		t.Errorf("unexpected Fprintln result %q %v", sb.String(), err)
+	}
+
+	// The formatting does not allocate when the buffer is reused.
+	buf = make([]byte, 0, 100)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = Appendf(buf[:0], "user=%s action=%s", "alice", SafeString("login"))
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations, got %v", allocs)
+	}
+}
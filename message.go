@@ -0,0 +1,124 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package redact
+
+import "github.com/cockroachdb/redact/internal/rfmt"
+
+// Message is a format string and its arguments, formatted lazily.
+//
+// A Message is printed like the result of Sprintf with the same
+// arguments, as it implements SafeFormatter. In addition, it exposes
+// its structure for the structured logging backends: the constant
+// format string, and the rendering and safety of each argument.
+type Message struct {
+	format string
+	args   []interface{}
+}
+
+// Msg returns a Message for the given format and arguments. As with
+// Sprintf, the format is considered safe.
+func Msg(format string, args ...interface{}) Message {
+	return Message{format: format, args: args}
+}
+
+// SafeFormat implements SafeFormatter.
+func (m Message) SafeFormat(w SafePrinter, _ rune) {
+	w.Printf(m.format, m.args...)
+}
+
+// RedactableString formats the message, like Sprintf.
+func (m Message) RedactableString() RedactableString {
+	return Sprintf(m.format, m.args...)
+}
+
+// FormatString returns the format string of the message.
+func (m Message) FormatString() string {
+	return m.format
+}
+
+// NumArgs returns the number of arguments of the message.
+func (m Message) NumArgs() int {
+	return len(m.args)
+}
+
+// Args renders each argument of the message on its own, using the
+// directive of the format string that prints it (e.g. %-5s), or %v for
+// the arguments that are not printed by a directive.
+func (m Message) Args() []MessageArg {
+	dirs := rfmt.ArgDirectives(m.format, m.args)
+	res := make([]MessageArg, len(m.args))
+	for i, a := range m.args {
+		s := Sprintf(dirs[i], a)
+		res[i] = MessageArg{Value: s, Safety: classify(s)}
+	}
+	return res
+}
+
+// MessageArg is an argument of a Message, rendered as a redactable
+// string.
+type MessageArg struct {
+	Value  RedactableString
+	Safety ArgSafety
+}
+
+// ArgSafety classifies the rendering of an argument of a Message.
+type ArgSafety int
+
+const (
+	// SafeArg is an argument rendered without unsafe data.
+	SafeArg ArgSafety = iota
+	// UnsafeArg is an argument rendered as a single unsafe region.
+	UnsafeArg
+	// HashArg is an argument rendered as a single unsafe region
+	// marked for hashing.
+	HashArg
+	// MixedArg is an argument rendered with both safe and unsafe
+	// data, for example by a SafeFormatter.
+	MixedArg
+)
+
+// String returns the name of the classification.
+func (s ArgSafety) String() string {
+	switch s {
+	case SafeArg:
+		return "safe"
+	case UnsafeArg:
+		return "unsafe"
+	case HashArg:
+		return "hash"
+	case MixedArg:
+		return "mixed"
+	}
+	return "unknown"
+}
+
+func classify(s RedactableString) ArgSafety {
+	spans := s.Spans()
+	unsafe := false
+	for _, sp := range spans {
+		if sp.Kind != SafeSpan {
+			unsafe = true
+		}
+	}
+	switch {
+	case !unsafe:
+		return SafeArg
+	case len(spans) > 1:
+		return MixedArg
+	case spans[0].Kind == HashSpan:
+		return HashArg
+	}
+	return UnsafeArg
+}
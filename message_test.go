@@ -0,0 +1,58 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package redact
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestMessage(t *testing.T) {
+	obj := compose{fn: func(w p) { w.Printf("id=%d", 42) }}
+	m := Msg("user %s (%-6v) from %v: %d%% of %s", "alice", SafeString("admin"), HashString("10.0.0.1"), 42, obj)
+
+	if m.FormatString() != "user %s (%-6v) from %v: %d%% of %s" || m.NumArgs() != 5 {
+		t.Errorf("unexpected structure: %q %d", m.FormatString(), m.NumArgs())
+	}
+	exp := Sprintf(m.FormatString(), "alice", SafeString("admin"), HashString("10.0.0.1"), 42, obj)
+	if s := Sprint(m); s != exp {
+		t.Errorf("expected %q, got %q", exp, s)
+	}
+	if s := m.RedactableString(); s != exp {
+		t.Errorf("expected %q, got %q", exp, s)
+	}
+	if s := Sprintf("[%v]", m); s != "["+exp+"]" {
+		t.Errorf("expected %q, got %q", "["+exp+"]", s)
+	}
+
+	expArgs := []MessageArg{
+		{"‹alice›", UnsafeArg},
+		{"admin ", SafeArg},
+		{"‹†10.0.0.1›", HashArg},
+		{"‹42›", UnsafeArg},
+		{"id=‹42›", MixedArg},
+	}
+	if args := m.Args(); !reflect.DeepEqual(args, expArgs) {
+		t.Errorf("expected %+v, got %+v", expArgs, args)
+	}
+	var kinds []string
+	for _, a := range expArgs {
+		kinds = append(kinds, a.Safety.String())
+	}
+	if s := fmt.Sprint(kinds); s != "[unsafe safe hash unsafe mixed]" {
+		t.Errorf("unexpected names: %s", s)
+	}
+}
@@ -1,10 +1,97 @@
 package otelprocessor
 
-import "go.opentelemetry.io/collector/component"
+import (
+	"fmt"
+	"regexp"
+
+	"go.opentelemetry.io/collector/component"
+)
 
 type Config struct {
+	// Detector configures an optional stage that looks for secrets
+	// and PII in the safe portions of log bodies and attributes,
+	// before they are redacted.
+	Detector DetectorConfig `mapstructure:"detector"`
+}
+
+// DetectorConfig configures the leak detector stage.
+type DetectorConfig struct {
+	// Enabled turns on the detector stage.
+	Enabled bool `mapstructure:"enabled"`
+	// Rules lists the names of the built-in rules to run. When empty,
+	// all the built-in rules are used.
+	Rules []string `mapstructure:"rules"`
+	// CustomRules adds user-defined rules to the built-in ones.
+	CustomRules []CustomRule `mapstructure:"custom_rules"`
+	// Action determines what happens to a record where a leak was
+	// detected. Defaults to DetectorActionRedact.
+	Action DetectorAction `mapstructure:"action"`
+}
+
+// CustomRule is a user-defined detector rule.
+type CustomRule struct {
+	// Name identifies the rule.
+	Name string `mapstructure:"name"`
+	// Pattern is the regular expression matching the sensitive text.
+	// If the expression contains a capturing group, only the text
+	// matched by the first group is considered sensitive.
+	Pattern string `mapstructure:"pattern"`
+	// Checksum, if set, names a checksum that the digits of a match
+	// must satisfy for it to be reported. The only supported value is
+	// "luhn".
+	Checksum string `mapstructure:"checksum"`
+}
+
+// DetectorAction is the action taken on records with a detected leak.
+type DetectorAction string
+
+const (
+	// DetectorActionRedact encloses the matches in redaction markers,
+	// so that they get redacted along with the other unsafe data.
+	DetectorActionRedact DetectorAction = "redact"
+	// DetectorActionTag leaves the record unchanged, but adds the
+	// LeakDetectedAttribute attribute to it.
+	DetectorActionTag DetectorAction = "tag"
+	// DetectorActionDrop removes the record from the pipeline.
+	DetectorActionDrop DetectorAction = "drop"
+)
+
+// LeakDetectedAttribute is the record attribute set by
+// DetectorActionTag.
+const LeakDetectedAttribute = "redact.leak_detected"
+
+// Validate checks the configuration for errors. It is invoked by the
+// collector when the configuration is loaded.
+func (cfg *Config) Validate() error {
+	d := &cfg.Detector
+	switch d.Action {
+	case "", DetectorActionRedact, DetectorActionTag, DetectorActionDrop:
+	default:
+		return fmt.Errorf("detector: unknown action %q", d.Action)
+	}
+	for _, name := range d.Rules {
+		if _, ok := builtinRules[name]; !ok {
+			return fmt.Errorf("detector: unknown rule %q", name)
+		}
+	}
+	for _, r := range d.CustomRules {
+		if r.Name == "" {
+			return fmt.Errorf("detector: custom rule with pattern %q has no name", r.Pattern)
+		}
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			return fmt.Errorf("detector: custom rule %q: %w", r.Name, err)
+		}
+		if _, ok := checksums[r.Checksum]; !ok {
+			return fmt.Errorf("detector: custom rule %q: unknown checksum %q", r.Name, r.Checksum)
+		}
+	}
+	return nil
 }
 
 func createDefaultConfig() component.Config {
-	return &Config{}
+	return &Config{
+		Detector: DetectorConfig{
+			Action: DetectorActionRedact,
+		},
+	}
 }
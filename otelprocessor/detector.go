@@ -0,0 +1,230 @@
+package otelprocessor
+
+import (
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/redact"
+)
+
+// rule is a detector rule: a regular expression that finds candidate
+// leaks, optionally filtered by a validation function.
+type rule struct {
+	name string
+	re   *regexp.Regexp
+	// valid, if set, filters out the candidate matches that are not
+	// actual leaks, e.g. because a checksum does not match.
+	valid func(match string) bool
+}
+
+// builtinRules are the rules available by name in the configuration.
+var builtinRules = map[string]rule{
+	"email": {
+		re: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	},
+	"credit_card": {
+		re: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+		valid: func(s string) bool {
+			n := countDigits(s)
+			return n >= 13 && n <= 19 && luhnValid(s)
+		},
+	},
+	"ipv4": {
+		re:    regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`),
+		valid: func(s string) bool { return net.ParseIP(s) != nil },
+	},
+	"ipv6": {
+		re: regexp.MustCompile(`(?i)(?:[0-9a-f]{0,4}:){2,7}[0-9a-f]{0,4}`),
+		valid: func(s string) bool {
+			ip := net.ParseIP(s)
+			return ip != nil && ip.To4() == nil && !ip.IsUnspecified()
+		},
+	},
+	"bearer_token": {
+		re: regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]+=*)`),
+	},
+	"aws_access_key": {
+		re: regexp.MustCompile(`\b((?:AKIA|ASIA|AGPA|AIDA|AROA|AIPA|ANPA|ANVA)[0-9A-Z]{16})\b`),
+	},
+	"aws_secret_key": {
+		re: regexp.MustCompile(`(?i)aws_?secret_?access_?key\W{0,3}([A-Za-z0-9/+=]{40})\b`),
+	},
+}
+
+// checksums are the validation functions available to custom rules.
+var checksums = map[string]func(string) bool{
+	"":     nil,
+	"luhn": luhnValid,
+}
+
+// luhnValid reports whether the digits in s, ignoring any other
+// character, pass the Luhn check.
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n > 1 && sum%10 == 0
+}
+
+func countDigits(s string) (n int) {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			n++
+		}
+	}
+	return n
+}
+
+// detector finds leaks in the safe portions of redactable strings.
+type detector struct {
+	rules  []rule
+	action DetectorAction
+}
+
+// newDetector instantiates the detector for the given configuration,
+// which must have been validated. It returns nil if the detector is
+// not enabled.
+func newDetector(cfg DetectorConfig) *detector {
+	if !cfg.Enabled {
+		return nil
+	}
+	d := &detector{action: cfg.Action}
+	if d.action == "" {
+		d.action = DetectorActionRedact
+	}
+	names := cfg.Rules
+	if len(names) == 0 {
+		for name := range builtinRules {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	for _, name := range names {
+		r := builtinRules[name]
+		r.name = name
+		d.rules = append(d.rules, r)
+	}
+	for _, c := range cfg.CustomRules {
+		d.rules = append(d.rules, rule{
+			name:  c.Name,
+			re:    regexp.MustCompile(c.Pattern),
+			valid: checksums[c.Checksum],
+		})
+	}
+	return d
+}
+
+// span is a byte range in a string.
+type span struct{ start, end int }
+
+// detect looks for leaks in the safe portions of s. If any is found,
+// it returns s with the leaks enclosed in redaction markers, and the
+// names of the rules that matched.
+func (d *detector) detect(s string) (string, []string) {
+	start, end := string(redact.StartMarker()), string(redact.EndMarker())
+
+	var leaks []span
+	var matched []string
+	// The safe portions are those that Redact() leaves in the output,
+	// including the contents of an unclosed marker.
+	for pos := 0; pos < len(s); {
+		safeEnd := len(s)
+		next := len(s)
+		if i := strings.Index(s[pos:], start); i != -1 {
+			if j := strings.Index(s[pos+i+len(start):], end); j != -1 {
+				safeEnd = pos + i
+				next = safeEnd + len(start) + j + len(end)
+			}
+		}
+		leaks, matched = d.appendLeaks(leaks, matched, s[pos:safeEnd], pos)
+		pos = next
+	}
+	if len(leaks) == 0 {
+		return s, nil
+	}
+
+	leaks = mergeSpans(leaks)
+	var buf strings.Builder
+	buf.Grow(len(s) + len(leaks)*(len(start)+len(end)))
+	last := 0
+	for _, l := range leaks {
+		buf.WriteString(s[last:l.start])
+		buf.WriteString(start)
+		buf.WriteString(s[l.start:l.end])
+		buf.WriteString(end)
+		last = l.end
+	}
+	buf.WriteString(s[last:])
+	return buf.String(), matched
+}
+
+// mergeSpans sorts the spans and merges those that overlap or touch,
+// so that each leak ends up in a single region.
+func mergeSpans(spans []span) []span {
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+	res := spans[:1]
+	for _, sp := range spans[1:] {
+		last := &res[len(res)-1]
+		if sp.start <= last.end {
+			if sp.end > last.end {
+				last.end = sp.end
+			}
+			continue
+		}
+		res = append(res, sp)
+	}
+	return res
+}
+
+// appendLeaks appends to leaks the spans of the leaks found in the
+// safe text, offset by base, and to matched the names of the rules
+// that found them.
+func (d *detector) appendLeaks(
+	leaks []span, matched []string, safe string, base int,
+) ([]span, []string) {
+	for _, r := range d.rules {
+		found := false
+		for _, loc := range r.re.FindAllStringSubmatchIndex(safe, -1) {
+			// Use the first capturing group, if any.
+			if len(loc) >= 4 && loc[2] >= 0 {
+				loc = loc[2:4]
+			}
+			if r.valid != nil && !r.valid(safe[loc[0]:loc[1]]) {
+				continue
+			}
+			if loc[0] == loc[1] {
+				continue
+			}
+			leaks = append(leaks, span{base + loc[0], base + loc[1]})
+			found = true
+		}
+		if found && !containsString(matched, r.name) {
+			matched = append(matched, r.name)
+		}
+	}
+	return leaks, matched
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
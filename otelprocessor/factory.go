@@ -20,7 +20,10 @@ func NewFactory() processor.Factory {
 
 func createLogsProcessor(ctx context.Context, params processor.Settings, baseCfg component.Config, next consumer.Logs) (processor.Logs, error) {
 	cfg := baseCfg.(*Config)
-	redactProcessor := newRedactProcessor(ctx, cfg)
+	redactProcessor, err := newRedactProcessor(ctx, params.TelemetrySettings, cfg)
+	if err != nil {
+		return nil, err
+	}
 	return processorhelper.NewLogs(
 		ctx,
 		params,
@@ -0,0 +1,85 @@
+package metadata
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ScopeName is the instrumentation scope under which the processor
+// reports its own telemetry.
+const ScopeName = "github.com/cockroachdb/redact/otelprocessor"
+
+// Meter returns the meter used for the processor's self-telemetry.
+func Meter(settings component.TelemetrySettings) metric.Meter {
+	return settings.MeterProvider.Meter(ScopeName)
+}
+
+// TelemetryBuilder holds the instruments used by the processor to
+// report on its own redaction activity.
+type TelemetryBuilder struct {
+	meter metric.Meter
+
+	// ProcessorRedactRecordsProcessed counts the records inspected.
+	ProcessorRedactRecordsProcessed metric.Int64Counter
+	// ProcessorRedactRecordsModified counts the records whose body or
+	// attributes were changed by redaction.
+	ProcessorRedactRecordsModified metric.Int64Counter
+	// ProcessorRedactRecordsDropped counts the records removed from
+	// the pipeline by the processor.
+	ProcessorRedactRecordsDropped metric.Int64Counter
+	// ProcessorRedactRedactedSpans counts the unsafe spans replaced
+	// by the redaction marker.
+	ProcessorRedactRedactedSpans metric.Int64Counter
+	// ProcessorRedactHashedSpans counts the unsafe spans replaced by
+	// a hash.
+	ProcessorRedactHashedSpans metric.Int64Counter
+	// ProcessorRedactBytesRemoved counts the bytes removed by
+	// redaction.
+	ProcessorRedactBytesRemoved metric.Int64Counter
+}
+
+// NewTelemetryBuilder creates the processor's instruments using the
+// meter provider from the provided settings.
+func NewTelemetryBuilder(settings component.TelemetrySettings) (*TelemetryBuilder, error) {
+	builder := TelemetryBuilder{meter: Meter(settings)}
+	var err, errs error
+	builder.ProcessorRedactRecordsProcessed, err = builder.meter.Int64Counter(
+		"otelcol_processor_redact_records_processed",
+		metric.WithDescription("Number of records inspected by the redact processor."),
+		metric.WithUnit("{records}"),
+	)
+	errs = errors.Join(errs, err)
+	builder.ProcessorRedactRecordsModified, err = builder.meter.Int64Counter(
+		"otelcol_processor_redact_records_modified",
+		metric.WithDescription("Number of records modified by the redact processor."),
+		metric.WithUnit("{records}"),
+	)
+	errs = errors.Join(errs, err)
+	builder.ProcessorRedactRecordsDropped, err = builder.meter.Int64Counter(
+		"otelcol_processor_redact_records_dropped",
+		metric.WithDescription("Number of records dropped by the redact processor."),
+		metric.WithUnit("{records}"),
+	)
+	errs = errors.Join(errs, err)
+	builder.ProcessorRedactRedactedSpans, err = builder.meter.Int64Counter(
+		"otelcol_processor_redact_redacted_spans",
+		metric.WithDescription("Number of unsafe spans replaced by the redaction marker."),
+		metric.WithUnit("{spans}"),
+	)
+	errs = errors.Join(errs, err)
+	builder.ProcessorRedactHashedSpans, err = builder.meter.Int64Counter(
+		"otelcol_processor_redact_hashed_spans",
+		metric.WithDescription("Number of unsafe spans replaced by a hash."),
+		metric.WithUnit("{spans}"),
+	)
+	errs = errors.Join(errs, err)
+	builder.ProcessorRedactBytesRemoved, err = builder.meter.Int64Counter(
+		"otelcol_processor_redact_bytes_removed",
+		metric.WithDescription("Number of bytes removed by redaction."),
+		metric.WithUnit("By"),
+	)
+	errs = errors.Join(errs, err)
+	return &builder, errs
+}
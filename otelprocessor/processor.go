@@ -4,44 +4,188 @@ import (
 	"context"
 
 	"github.com/cockroachdb/redact"
+	"github.com/cockroachdb/redact/otelprocessor/metadata"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
 )
 
+// logsPipelineAttrs tags the self-telemetry reported while processing
+// logs.
+var logsPipelineAttrs = metric.WithAttributeSet(attribute.NewSet(attribute.String("pipeline", "logs")))
+
 type redactProcessor struct {
-	config Config
+	config    Config
+	logger    *zap.Logger
+	telemetry *metadata.TelemetryBuilder
+	// detector is nil if leak detection is disabled.
+	detector *detector
 }
 
-func newRedactProcessor(_ context.Context, config *Config) *redactProcessor {
+func newRedactProcessor(
+	_ context.Context, set component.TelemetrySettings, config *Config,
+) (*redactProcessor, error) {
+	telemetry, err := metadata.NewTelemetryBuilder(set)
+	if err != nil {
+		return nil, err
+	}
 	rp := &redactProcessor{
-		config: *config,
+		config:    *config,
+		logger:    set.Logger,
+		telemetry: telemetry,
+		detector:  newDetector(config.Detector),
 	}
 
-	return rp
+	return rp, nil
+}
+
+// redactStats accumulates the redaction activity over one batch, so
+// that the counters are updated once per batch rather than once per
+// record.
+type redactStats struct {
+	recordsProcessed int64
+	recordsModified  int64
+	recordsDropped   int64
+	redactedSpans    int64
+	hashedSpans      int64
+	bytesRemoved     int64
 }
 
-func (rp *redactProcessor) processLogs(_ context.Context, logs plog.Logs) (plog.Logs, error) {
+func (rp *redactProcessor) processLogs(ctx context.Context, logs plog.Logs) (plog.Logs, error) {
+	var stats redactStats
 	resourceLogs := logs.ResourceLogs()
 	for i := 0; i < resourceLogs.Len(); i++ {
-		rp.processResourceLog(resourceLogs.At(i))
+		rp.processResourceLog(resourceLogs.At(i), &stats)
 	}
+	rp.recordStats(ctx, &stats, logsPipelineAttrs)
 
 	return logs, nil
 }
 
-func (rp *redactProcessor) processResourceLog(rl plog.ResourceLogs) {
+func (rp *redactProcessor) processResourceLog(rl plog.ResourceLogs, stats *redactStats) {
 	for i := 0; i < rl.ScopeLogs().Len(); i++ {
 		ils := rl.ScopeLogs().At(i)
-		for j := 0; j < ils.LogRecords().Len(); j++ {
-			log := ils.LogRecords().At(j)
-			rp.processLogBody(log.Body())
+		ils.LogRecords().RemoveIf(func(log plog.LogRecord) bool {
+			return rp.processLogRecord(log, stats)
+		})
+	}
+}
+
+// processLogRecord processes one record and reports whether it must
+// be dropped.
+func (rp *redactProcessor) processLogRecord(log plog.LogRecord, stats *redactStats) (drop bool) {
+	stats.recordsProcessed++
+	if rp.detector != nil && rp.detectLeaks(log) {
+		switch rp.detector.action {
+		case DetectorActionDrop:
+			stats.recordsDropped++
+			return true
+		case DetectorActionTag:
+			log.Attributes().PutBool(LeakDetectedAttribute, true)
 		}
 	}
+	if rp.processLogBody(log.Body(), stats) {
+		stats.recordsModified++
+	}
+	return false
 }
 
-func (rp *redactProcessor) processLogBody(body pcommon.Value) {
-	if body.Type() == pcommon.ValueTypeStr {
-		red := redact.RedactableString(body.AsString())
-		body.SetStr(string(red.Redact()))
+// detectLeaks runs the detector over the body and string attributes of
+// the record, and reports whether a leak was found. With
+// DetectorActionRedact, the leaks are enclosed in redaction markers:
+// the subsequent redaction removes them from the body, and they are
+// left marked in the attributes.
+func (rp *redactProcessor) detectLeaks(log plog.LogRecord) bool {
+	var rules, keys []string
+	detectValue := func(v pcommon.Value) bool {
+		if v.Type() != pcommon.ValueTypeStr {
+			return false
+		}
+		res, matched := rp.detector.detect(v.Str())
+		if matched == nil {
+			return false
+		}
+		for _, r := range matched {
+			if !containsString(rules, r) {
+				rules = append(rules, r)
+			}
+		}
+		if rp.detector.action == DetectorActionRedact {
+			v.SetStr(res)
+		}
+		return true
+	}
+	found := detectValue(log.Body())
+	log.Attributes().Range(func(k string, v pcommon.Value) bool {
+		if detectValue(v) {
+			keys = append(keys, k)
+		}
+		return true
+	})
+	if found || len(keys) > 0 {
+		// Only the rule names and attribute keys are logged: the
+		// values contain the very data that was detected.
+		rp.logger.Debug("leak detected in log record",
+			zap.Strings("rules", rules),
+			zap.Bool("body", found),
+			zap.Strings("keys", keys))
+		return true
 	}
+	return false
+}
+
+func (rp *redactProcessor) processLogBody(body pcommon.Value, stats *redactStats) bool {
+	return rp.processValue(body, stats)
+}
+
+// processValue redacts the value in place if it is a string, and
+// reports whether it was modified.
+func (rp *redactProcessor) processValue(v pcommon.Value, stats *redactStats) bool {
+	if v.Type() != pcommon.ValueTypeStr {
+		return false
+	}
+	orig := v.Str()
+	red := string(redact.RedactableString(orig).Redact())
+	if red == orig {
+		return false
+	}
+	redacted, hashed := countSpans(orig, redact.IsHashingEnabled())
+	stats.redactedSpans += int64(redacted)
+	stats.hashedSpans += int64(hashed)
+	if removed := len(orig) - len(red); removed > 0 {
+		stats.bytesRemoved += int64(removed)
+	}
+	v.SetStr(red)
+	return true
+}
+
+// countSpans counts the unsafe spans in s that Redact() replaces,
+// distinguishing those that are replaced by a hash. The malformed
+// markers are counted as repaired by Spans.
+func countSpans(s string, hashEnabled bool) (redacted, hashed int) {
+	for _, sp := range redact.RedactableString(s).Spans() {
+		switch {
+		case sp.Kind == redact.SafeSpan:
+		case sp.Kind == redact.HashSpan && hashEnabled:
+			hashed++
+		default:
+			redacted++
+		}
+	}
+	return redacted, hashed
+}
+
+func (rp *redactProcessor) recordStats(
+	ctx context.Context, stats *redactStats, attrs metric.MeasurementOption,
+) {
+	t := rp.telemetry
+	t.ProcessorRedactRecordsProcessed.Add(ctx, stats.recordsProcessed, attrs)
+	t.ProcessorRedactRecordsModified.Add(ctx, stats.recordsModified, attrs)
+	t.ProcessorRedactRecordsDropped.Add(ctx, stats.recordsDropped, attrs)
+	t.ProcessorRedactRedactedSpans.Add(ctx, stats.redactedSpans, attrs)
+	t.ProcessorRedactHashedSpans.Add(ctx, stats.hashedSpans, attrs)
+	t.ProcessorRedactBytesRemoved.Add(ctx, stats.bytesRemoved, attrs)
 }
@@ -2,12 +2,20 @@ package otelprocessor
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/cockroachdb/redact"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/pdata/plog"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 type TestConfig struct {
@@ -27,10 +35,100 @@ func TestLogBody(t *testing.T) {
 	logEntry.Body().SetStr(string(body))
 
 	ctx := context.Background()
-	processor := newRedactProcessor(ctx, &Config{})
+	processor, err := newRedactProcessor(ctx, componenttest.NewNopTelemetrySettings(), &Config{})
+	require.NoError(t, err)
 	outBatch, err := processor.processLogs(ctx, inBatch)
 	assert.NoError(t, err)
 	outLogBody := outBatch.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body().Str()
 
 	require.Equal(t, string(body.Redact()), outLogBody)
 }
+
+func TestLogAttributes(t *testing.T) {
+	// The attributes are not redacted.
+	attr := string(redact.Sprintf("user %s", "alice"))
+	inBatch := plog.NewLogs()
+	rec := inBatch.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	rec.Attributes().PutStr("user", attr)
+
+	ctx := context.Background()
+	processor, err := newRedactProcessor(ctx, componenttest.NewNopTelemetrySettings(), &Config{})
+	require.NoError(t, err)
+	outBatch, err := processor.processLogs(ctx, inBatch)
+	require.NoError(t, err)
+	v, _ := outBatch.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Attributes().Get("user")
+	assert.Equal(t, attr, v.Str())
+}
+
+func TestSelfTelemetry(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	core, logs := observer.New(zapcore.DebugLevel)
+	set := component.TelemetrySettings{
+		Logger:        zap.New(core),
+		MeterProvider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	}
+
+	inBatch := plog.NewLogs()
+	records := inBatch.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	// A record with a redacted body, and a leak in an attribute.
+	rec := records.AppendEmpty()
+	rec.Body().SetStr(string(redact.Sprintf("hello %s", "world")))
+	rec.Attributes().PutStr("peer", "alice@example.com")
+	// A record with a hash marker.
+	rec = records.AppendEmpty()
+	rec.Body().SetStr(string(redact.Sprintf("user %s", redact.HashString("bob"))))
+	// A record without any marker.
+	rec = records.AppendEmpty()
+	rec.Body().SetStr("plain")
+
+	redact.EnableHashing(nil)
+	defer redact.DisableHashing()
+
+	ctx := context.Background()
+	cfg := &Config{Detector: DetectorConfig{
+		Enabled: true,
+		Rules:   []string{"email"},
+		Action:  DetectorActionTag,
+	}}
+	processor, err := newRedactProcessor(ctx, set, cfg)
+	require.NoError(t, err)
+	_, err = processor.processLogs(ctx, inBatch)
+	require.NoError(t, err)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	got := map[string]int64{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		sum := m.Data.(metricdata.Sum[int64])
+		require.Len(t, sum.DataPoints, 1)
+		dp := sum.DataPoints[0]
+		pipeline, ok := dp.Attributes.Value("pipeline")
+		require.True(t, ok)
+		assert.Equal(t, "logs", pipeline.AsString())
+		got[m.Name] = dp.Value
+	}
+	// ‹world› shrinks by 5-2 = 3 bytes (the contents are replaced by
+	// ×, which is 2 bytes long). The hash of
+	// ‹†bob› is 8 bytes long, so that span grows by 8-3-3 = 2 bytes and
+	// does not count as removed.
+	assert.Equal(t, map[string]int64{
+		"otelcol_processor_redact_records_processed": 3,
+		"otelcol_processor_redact_records_modified":  2,
+		"otelcol_processor_redact_records_dropped":   0,
+		"otelcol_processor_redact_redacted_spans":    1,
+		"otelcol_processor_redact_hashed_spans":      1,
+		"otelcol_processor_redact_bytes_removed":     3,
+	}, got)
+
+	// The keys of the attributes with a leak are logged, never their
+	// values.
+	entries := logs.FilterMessage("leak detected in log record").All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, []interface{}{"peer"}, entries[0].ContextMap()["keys"])
+	for _, e := range logs.All() {
+		for _, v := range e.ContextMap() {
+			assert.NotContains(t, fmt.Sprint(v), "alice")
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package render displays redactable strings with their unsafe regions
+// highlighted, so that the reader can see at a glance which data would
+// be redacted.
+//
+// The renderers work on the regions returned by the Spans method of
+// RedactableString. The markers themselves are not displayed.
+// Malformed markers are repaired as by Repair, so that unsafe text is
+// never displayed as safe.
+package render
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/redact"
+)
+
+// CSS classes of the HTML span elements enclosing the unsafe regions.
+const (
+	UnsafeClass   = "redact-unsafe"
+	HashClass     = "redact-hash"
+	RedactedClass = "redact-redacted"
+)
+
+// DefaultCSS is a style sheet for the HTML output.
+const DefaultCSS = `.redact-unsafe { background-color: #fdd; }
+.redact-hash { background-color: #ffc; }
+.redact-redacted { background-color: #ddd; color: #666; }
+`
+
+// HTML renders s as HTML text. The unsafe regions are enclosed in span
+// elements of class UnsafeClass, HashClass or RedactedClass depending
+// on their kind. All the text is HTML-escaped. Line breaks are
+// preserved, so the output is meant to be displayed in a pre element.
+func HTML(s redact.RedactableString) string {
+	var buf strings.Builder
+	for _, sp := range s.Spans() {
+		class := spanClass(sp.Kind)
+		if class != "" {
+			buf.WriteString(`<span class="`)
+			buf.WriteString(class)
+			buf.WriteString(`">`)
+		}
+		buf.WriteString(html.EscapeString(sp.Text))
+		if class != "" {
+			buf.WriteString("</span>")
+		}
+	}
+	return buf.String()
+}
+
+func spanClass(k redact.SpanKind) string {
+	switch k {
+	case redact.UnsafeSpan:
+		return UnsafeClass
+	case redact.HashSpan:
+		return HashClass
+	case redact.RedactedSpan:
+		return RedactedClass
+	}
+	return ""
+}
+
+// ANSI escape sequences used to highlight the unsafe regions.
+const (
+	ansiUnsafe   = "\x1b[1;31m" // bold red
+	ansiHash     = "\x1b[33m"   // yellow
+	ansiRedacted = "\x1b[2m"    // dim
+	ansiReset    = "\x1b[0m"
+)
+
+// ANSI renders s as text for a terminal, with the unsafe regions
+// highlighted using ANSI color escape sequences: unsafe text in bold
+// red, text marked for hashing in yellow and redacted text dimmed.
+//
+// The control characters in s other than tabs and newlines are
+// displayed in caret notation (e.g. ^[ for the escape character), so
+// that the text cannot interfere with the highlighting.
+func ANSI(s redact.RedactableString) string {
+	var buf strings.Builder
+	for _, sp := range s.Spans() {
+		seq := spanSequence(sp.Kind)
+		buf.WriteString(seq)
+		writeCaret(&buf, sp.Text)
+		if seq != "" {
+			buf.WriteString(ansiReset)
+		}
+	}
+	return buf.String()
+}
+
+func spanSequence(k redact.SpanKind) string {
+	switch k {
+	case redact.UnsafeSpan:
+		return ansiUnsafe
+	case redact.HashSpan:
+		return ansiHash
+	case redact.RedactedSpan:
+		return ansiRedacted
+	}
+	return ""
+}
+
+// writeCaret writes s to buf, with the control characters other than
+// tabs and newlines in caret notation. The C1 control characters, which
+// have no caret notation, are replaced by U+FFFD.
+func writeCaret(buf *strings.Builder, s string) {
+	for _, r := range s {
+		switch {
+		case r == '\t' || r == '\n':
+			buf.WriteRune(r)
+		case r < 0x20:
+			buf.WriteByte('^')
+			buf.WriteRune(r + '@')
+		case r == 0x7f:
+			buf.WriteString("^?")
+		case 0x80 <= r && r < 0xa0:
+			buf.WriteRune(utf8.RuneError)
+		default:
+			buf.WriteRune(r)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package render
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/redact"
+)
+
+func TestRender(t *testing.T) {
+	testCases := []struct {
+		input string
+		html  string
+		ansi  string
+	}{
+		{"", "", ""},
+		{"plain <b>", "plain &lt;b&gt;", "plain <b>"},
+		{
+			"user ‹<alice>› said ‹†hi›",
+			`user <span class="redact-unsafe">&lt;alice&gt;</span> said <span class="redact-hash">hi</span>`,
+			"user \x1b[1;31m<alice>\x1b[0m said \x1b[33mhi\x1b[0m",
+		},
+		{
+			"x ‹×›\n",
+			`x <span class="redact-redacted">×</span>` + "\n",
+			"x \x1b[2m×\x1b[0m\n",
+		},
+		{
+			"‹a\x1b[0mb›\t\u009b",
+			`<span class="redact-unsafe">a` + "\x1b" + `[0mb</span>` + "\t\u009b",
+			"\x1b[1;31ma^[[0mb\x1b[0m\t�",
+		},
+		// Malformed markers are displayed as unsafe.
+		{
+			"user ‹alice",
+			`user <span class="redact-unsafe">alice</span>`,
+			"user \x1b[1;31malice\x1b[0m",
+		},
+	}
+	for _, tc := range testCases {
+		s := redact.RedactableString(tc.input)
+		if actual := HTML(s); actual != tc.html {
+			t.Errorf("%q: expected HTML %q, got %q", tc.input, tc.html, actual)
+		}
+		if actual := ANSI(s); actual != tc.ansi {
+			t.Errorf("%q: expected ANSI %q, got %q", tc.input, tc.ansi, actual)
+		}
+	}
+}
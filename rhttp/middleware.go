@@ -0,0 +1,101 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rhttp
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/cockroachdb/redact"
+)
+
+// Logger receives the entries logged by Middleware.
+type Logger func(ctx context.Context, entry redact.RedactableString)
+
+// Middleware returns a handler that serves the requests with next, then
+// logs them with log, e.g.:
+//
+//	GET /search?q=‹cats› HTTP/1.1 map[Accept:[‹*/*›]] from ‹10.0.0.1:1234›: 200 OK, 512 bytes in 1.2ms
+//
+// If next panics, the request is logged as panicked, without a status,
+// and the panic is propagated.
+func Middleware(next http.Handler, log Logger, opts *Options) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rw := &responseWriter{ResponseWriter: w}
+		defer func() {
+			if p := recover(); p != nil {
+				log(r.Context(), redact.Sprintf("%+v from %s: panicked after %d bytes in %s",
+					Request(r, opts), r.RemoteAddr,
+					redact.SafeInt(rw.written), redact.Safe(time.Since(start))))
+				panic(p)
+			}
+			status := rw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			log(r.Context(), redact.Sprintf("%+v from %s: %d %s, %d bytes in %s",
+				Request(r, opts), r.RemoteAddr,
+				redact.SafeInt(status), redact.SafeString(http.StatusText(status)),
+				redact.SafeInt(rw.written), redact.Safe(time.Since(start))))
+		}()
+		var ww http.ResponseWriter = rw
+		if _, ok := w.(http.Flusher); ok {
+			ww = flushWriter{rw}
+		}
+		next.ServeHTTP(ww, r)
+	})
+}
+
+// responseWriter records the status and the size of a response.
+type responseWriter struct {
+	http.ResponseWriter
+	status  int
+	written int64
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter.
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.written += int64(n)
+	return n, err
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// flushWriter is a responseWriter whose underlying writer implements
+// http.Flusher. The other optional interfaces are reached through
+// Unwrap.
+type flushWriter struct {
+	*responseWriter
+}
+
+// Flush implements http.Flusher.
+func (w flushWriter) Flush() {
+	w.ResponseWriter.(http.Flusher).Flush()
+}
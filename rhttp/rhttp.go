@@ -0,0 +1,214 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package rhttp renders HTTP requests, responses and headers as
+// redactable strings.
+//
+// The method, the protocol, the status and the header names are safe.
+// The header values are unsafe, except for the headers listed in
+// Options.SafeHeaders. The values of the denied headers (see
+// DeniedHeaders), which carry credentials, are never printed: they are
+// replaced by the redacted marker ‹×› even before redaction. The
+// request URLs are rendered by stdsafe.SafeURL.
+package rhttp
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/cockroachdb/redact"
+	"github.com/cockroachdb/redact/stdsafe"
+)
+
+// deniedHeaders are the headers whose values are always hidden.
+var deniedHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Proxy-Authorization",
+	"Set-Cookie",
+}
+
+// DeniedHeaders returns the headers whose values are always hidden,
+// whatever the options.
+func DeniedHeaders() []string {
+	return append([]string(nil), deniedHeaders...)
+}
+
+// headerRendering is the rendering of the values of a header.
+type headerRendering int
+
+const (
+	// headerUnsafe marks the values as unsafe.
+	headerUnsafe headerRendering = iota
+	// headerSafe prints the values safely.
+	headerSafe
+	// headerHidden replaces the values by the redacted marker, ‹×›.
+	headerHidden
+)
+
+// Options configures the rendering. The zero value, and a nil
+// *Options, use the defaults.
+type Options struct {
+	// SafeHeaders are the headers whose values are safe. The denied
+	// headers remain hidden even when they are listed here.
+	SafeHeaders []string
+	// HiddenHeaders are the headers whose values are hidden in
+	// addition to the denied headers.
+	HiddenHeaders []string
+	// URL configures the rendering of the request URLs.
+	URL stdsafe.URLOptions
+}
+
+// headerRendering returns the rendering of the values of the header.
+func (o *Options) headerRendering(name string) headerRendering {
+	name = http.CanonicalHeaderKey(name)
+	if contains(deniedHeaders, name) {
+		return headerHidden
+	}
+	if o == nil {
+		return headerUnsafe
+	}
+	if contains(o.HiddenHeaders, name) {
+		return headerHidden
+	}
+	if contains(o.SafeHeaders, name) {
+		return headerSafe
+	}
+	return headerUnsafe
+}
+
+func (o *Options) urlOptions() stdsafe.URLOptions {
+	if o == nil {
+		return stdsafe.URLOptions{}
+	}
+	return o.URL
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if http.CanonicalHeaderKey(n) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Header returns a SafeFormatter rendering h like fmt renders an
+// http.Header, e.g. map[Accept:[text/html] Cookie:[‹×›]].
+func Header(h http.Header, opts *Options) redact.SafeFormatter {
+	return headerFormatter{h: h, opts: opts}
+}
+
+type headerFormatter struct {
+	h    http.Header
+	opts *Options
+}
+
+// SafeFormat implements redact.SafeFormatter.
+func (f headerFormatter) SafeFormat(p redact.SafePrinter, _ rune) {
+	names := make([]string, 0, len(f.h))
+	for name := range f.h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	p.SafeString("map[")
+	for i, name := range names {
+		if i > 0 {
+			p.SafeRune(' ')
+		}
+		p.SafeString(redact.SafeString(name))
+		p.SafeString(":[")
+		r := f.opts.headerRendering(name)
+		for j, v := range f.h[name] {
+			if j > 0 {
+				p.SafeRune(' ')
+			}
+			switch r {
+			case headerSafe:
+				p.SafeString(redact.SafeString(v))
+			case headerHidden:
+				p.Print(redact.RedactableString(redact.RedactedMarker()))
+			default:
+				p.Print(v)
+			}
+		}
+		p.SafeRune(']')
+	}
+	p.SafeRune(']')
+}
+
+// Request returns a SafeFormatter rendering the request line of r,
+// e.g. GET /search?q=‹cats› HTTP/1.1. With the + flag (%+v), the
+// headers follow.
+func Request(r *http.Request, opts *Options) redact.SafeFormatter {
+	return requestFormatter{r: r, opts: opts}
+}
+
+type requestFormatter struct {
+	r    *http.Request
+	opts *Options
+}
+
+// SafeFormat implements redact.SafeFormatter.
+func (f requestFormatter) SafeFormat(p redact.SafePrinter, _ rune) {
+	r := f.r
+	if r == nil {
+		p.SafeString("<nil>")
+		return
+	}
+	method := r.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	u := r.URL
+	if u == nil {
+		u = &url.URL{}
+	}
+	p.Printf("%s %v %s",
+		redact.SafeString(method), stdsafe.SafeURL(u, f.opts.urlOptions()), redact.SafeString(r.Proto))
+	if p.Flag('+') {
+		p.Printf(" %v", Header(r.Header, f.opts))
+	}
+}
+
+// Response returns a SafeFormatter rendering the status line of r,
+// e.g. HTTP/1.1 200 OK. With the + flag (%+v), the headers follow.
+func Response(r *http.Response, opts *Options) redact.SafeFormatter {
+	return responseFormatter{r: r, opts: opts}
+}
+
+type responseFormatter struct {
+	r    *http.Response
+	opts *Options
+}
+
+// SafeFormat implements redact.SafeFormatter.
+func (f responseFormatter) SafeFormat(p redact.SafePrinter, _ rune) {
+	r := f.r
+	if r == nil {
+		p.SafeString("<nil>")
+		return
+	}
+	status := r.Status
+	if status == "" {
+		status = http.StatusText(r.StatusCode)
+		p.Printf("%s %d %s", redact.SafeString(r.Proto), redact.SafeInt(r.StatusCode), redact.SafeString(status))
+	} else {
+		p.Printf("%s %s", redact.SafeString(r.Proto), redact.SafeString(status))
+	}
+	if p.Flag('+') {
+		p.Printf(" %v", Header(r.Header, f.opts))
+	}
+}
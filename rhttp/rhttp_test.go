@@ -0,0 +1,172 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rhttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/redact"
+)
+
+func TestHeader(t *testing.T) {
+	h := http.Header{
+		"Accept":        {"text/html", "*/*"},
+		"Authorization": {"Bearer secret"},
+		"Set-Cookie":    {"a=1", "b=2"},
+		"X-Request-Id":  {"42"},
+		"x-api-key":     {"key"},
+	}
+	testCases := []struct {
+		opts *Options
+		exp  redact.RedactableString
+	}{
+		{nil, "map[Accept:[‹text/html› ‹*/*›] Authorization:[‹×›] Set-Cookie:[‹×› ‹×›] " +
+			"X-Request-Id:[‹42›] x-api-key:[‹key›]]"},
+		{&Options{
+			SafeHeaders:   []string{"accept", "X-Request-ID", "Authorization"},
+			HiddenHeaders: []string{"X-Api-Key"},
+		}, "map[Accept:[text/html */*] Authorization:[‹×›] Set-Cookie:[‹×› ‹×›] " +
+			"X-Request-Id:[42] x-api-key:[‹×›]]"},
+	}
+	for _, tc := range testCases {
+		actual := redact.Sprint(Header(h, tc.opts))
+		if actual != tc.exp {
+			t.Errorf("expected %q, got %q", tc.exp, actual)
+		}
+		// The denied values are gone even before redaction.
+		if s := actual.StripMarkers(); strings.Contains(s, "secret") || strings.Contains(s, "a=1") {
+			t.Errorf("denied header value printed: %q", s)
+		}
+	}
+
+	// The denied headers cannot be changed through DeniedHeaders.
+	DeniedHeaders()[0] = "Accept"
+	if actual := redact.Sprint(Header(h, nil)); actual != testCases[0].exp {
+		t.Errorf("expected %q, got %q", testCases[0].exp, actual)
+	}
+}
+
+func TestRequestResponse(t *testing.T) {
+	r := httptest.NewRequest("POST", "/login?user=alice&next=/home", nil)
+	r.Header.Set("Cookie", "session=1234")
+	r.Header.Set("Content-Type", "application/json")
+	opts := &Options{SafeHeaders: []string{"Content-Type"}}
+	opts.URL.SafeParams = []string{"next"}
+
+	const exp = "POST /login?user=‹alice›&next=/home HTTP/1.1"
+	if actual := redact.Sprint(Request(r, opts)); actual != exp {
+		t.Errorf("expected %q, got %q", exp, actual)
+	}
+	const expHeaders = exp + " map[Content-Type:[application/json] Cookie:[‹×›]]"
+	if actual := redact.Sprintf("%+v", Request(r, opts)); actual != expHeaders {
+		t.Errorf("expected %q, got %q", expHeaders, actual)
+	}
+
+	resp := &http.Response{
+		Proto:      "HTTP/1.1",
+		StatusCode: http.StatusNotFound,
+		Header:     http.Header{"Set-Cookie": {"session=1234"}},
+	}
+	if actual := redact.Sprintf("%+v", Response(resp, nil)); actual != "HTTP/1.1 404 Not Found map[Set-Cookie:[‹×›]]" {
+		t.Errorf("unexpected response rendering: %q", actual)
+	}
+	resp.Status = "200 OK"
+	if actual := redact.Sprint(Response(resp, nil)); actual != "HTTP/1.1 200 OK" {
+		t.Errorf("unexpected response rendering: %q", actual)
+	}
+	if actual := redact.Sprint(Request(nil, nil), Response(nil, nil)); actual != "<nil> <nil>" {
+		t.Errorf("unexpected nil rendering: %q", actual)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var entries []redact.RedactableString
+	log := func(_ context.Context, entry redact.RedactableString) { entries = append(entries, entry) }
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("hello"))
+	}), log, nil)
+
+	for _, path := range []string{"/hello?name=bob", "/missing"} {
+		r := httptest.NewRequest("GET", path, nil)
+		r.Header.Set("Authorization", "Basic xyz")
+		h.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	const exp0 = "GET /hello?name=‹bob› HTTP/1.1 map[Authorization:[‹×›]] from ‹192.0.2.1:1234›: 200 OK, 5 bytes in "
+	const exp1 = "GET /missing HTTP/1.1 map[Authorization:[‹×›]] from ‹192.0.2.1:1234›: 404 Not Found, 19 bytes in "
+	for i, exp := range []string{exp0, exp1} {
+		if !strings.HasPrefix(string(entries[i]), exp) {
+			t.Errorf("expected %q..., got %q", exp, entries[i])
+		}
+		if err := entries[i].Validate(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestMiddlewareFlusher(t *testing.T) {
+	log := func(context.Context, redact.RedactableString) {}
+	var flusher bool
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, flusher = w.(http.Flusher)
+	}), log, nil)
+
+	// httptest.ResponseRecorder implements http.Flusher.
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if !flusher {
+		t.Error("expected the writer to implement http.Flusher")
+	}
+	// A writer that does not implement it.
+	h.ServeHTTP(struct{ http.ResponseWriter }{rec}, httptest.NewRequest("GET", "/", nil))
+	if flusher {
+		t.Error("expected the writer not to implement http.Flusher")
+	}
+}
+
+func TestMiddlewarePanic(t *testing.T) {
+	var entries []redact.RedactableString
+	log := func(_ context.Context, entry redact.RedactableString) { entries = append(entries, entry) }
+	h := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("partial"))
+		panic("boom")
+	}), log, nil)
+
+	func() {
+		defer func() {
+			if p := recover(); p != "boom" {
+				t.Errorf("expected the panic to propagate, got %v", p)
+			}
+		}()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	}()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	const exp = "GET / HTTP/1.1 map[] from ‹192.0.2.1:1234›: panicked after 7 bytes in "
+	if !strings.HasPrefix(string(entries[0]), exp) {
+		t.Errorf("expected %q..., got %q", exp, entries[0])
+	}
+}
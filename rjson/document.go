@@ -0,0 +1,354 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DocumentOptions configures AnnotateDocument and RedactDocument.
+type DocumentOptions struct {
+	// Safe are the selectors of the safe values. All the other
+	// string and number leaves are unsafe. A selector selecting an
+	// object or an array selects all the values inside it.
+	//
+	// The selectors are a subset of JSONPath:
+	//
+	//	$             the whole document
+	//	.name         the member of an object named name
+	//	['name']      idem, for the names containing special characters
+	//	[3]           the element of an array at index 3
+	//	.* or [*]     any member of an object or element of an array
+	//	..name        the members named name at any depth
+	//
+	// For example, $.items[*].id selects the id of all the items.
+	Safe []string
+	// NumberSentinel is the number replacing the unsafe numbers in
+	// the output of RedactDocument. It defaults to 0.
+	NumberSentinel json.Number
+}
+
+// AnnotateDocument reads the JSON values from src and writes them to
+// dst as redactable JSON: the unsafe strings and numbers are enclosed
+// in redaction markers, as in the output of Marshal. The order of the
+// object members is preserved. The document is processed as a stream,
+// without loading it fully in memory. If src holds several JSON
+// values, e.g. in the JSON Lines format, they are processed in turn,
+// and each of them is followed by a newline character.
+func AnnotateDocument(dst io.Writer, src io.Reader, opts DocumentOptions) error {
+	return processDocument(dst, src, opts, false)
+}
+
+// RedactDocument is like AnnotateDocument but writes the redacted JSON
+// directly. The types of the values are preserved: the unsafe strings
+// are replaced by "‹×›" and the unsafe numbers by
+// opts.NumberSentinel.
+func RedactDocument(dst io.Writer, src io.Reader, opts DocumentOptions) error {
+	return processDocument(dst, src, opts, true)
+}
+
+func processDocument(dst io.Writer, src io.Reader, opts DocumentOptions, redacted bool) error {
+	d := documentState{dst: dst, redacted: redacted, sentinel: opts.NumberSentinel}
+	if d.sentinel == "" {
+		d.sentinel = "0"
+	} else if !isJSONNumber(d.sentinel) {
+		return fmt.Errorf("rjson: invalid number sentinel %q", d.sentinel)
+	}
+	var initial []selectorState
+	for _, s := range opts.Safe {
+		sel, err := parseSelector(s)
+		if err != nil {
+			return err
+		}
+		initial = append(initial, selectorState{sel: sel})
+	}
+	d.dec = json.NewDecoder(src)
+	d.dec.UseNumber()
+	for {
+		err := d.value(initial)
+		if err == io.EOF {
+			return d.flush()
+		}
+		if err != nil {
+			return err
+		}
+		d.WriteByte('\n')
+		if err := d.flush(); err != nil {
+			return err
+		}
+	}
+}
+
+// isJSONNumber returns true if n is a number in the JSON syntax, as
+// opposed to, e.g., NaN or 0x10 which strconv.ParseFloat accepts.
+func isJSONNumber(n json.Number) bool {
+	if !json.Valid([]byte(n)) {
+		return false
+	}
+	dec := json.NewDecoder(strings.NewReader(string(n)))
+	dec.UseNumber()
+	tok, err := dec.Token()
+	return err == nil && tok == n
+}
+
+// documentState is the state of AnnotateDocument and RedactDocument.
+// The output is accumulated in the encodeState and flushed to dst
+// periodically.
+type documentState struct {
+	encodeState
+	dec      *json.Decoder
+	dst      io.Writer
+	redacted bool
+	sentinel json.Number
+}
+
+// flushSize is the size of the output accumulated before it is
+// flushed.
+const flushSize = 32 << 10
+
+func (d *documentState) flush() error {
+	_, err := d.dst.Write(d.Bytes())
+	d.Reset()
+	return err
+}
+
+// value processes the next value of the document, at the position
+// reached by the selector states.
+func (d *documentState) value(states []selectorState) error {
+	if d.Len() > flushSize {
+		if err := d.flush(); err != nil {
+			return err
+		}
+	}
+	tok, err := d.dec.Token()
+	if err != nil {
+		return err
+	}
+	o := noOverride
+	if selected(states) {
+		o = overrideSafe
+	}
+	switch tok := tok.(type) {
+	case json.Delim:
+		switch tok {
+		case '{':
+			d.WriteByte('{')
+			for first := true; d.dec.More(); first = false {
+				if !first {
+					d.WriteByte(',')
+				}
+				k, err := d.dec.Token()
+				if err != nil {
+					return unexpectedEOF(err)
+				}
+				name := k.(string)
+				d.key(name)
+				if err := d.value(advance(states, pathElem{name: name, index: -1})); err != nil {
+					return unexpectedEOF(err)
+				}
+			}
+			d.WriteByte('}')
+		case '[':
+			d.WriteByte('[')
+			for i := 0; d.dec.More(); i++ {
+				if i > 0 {
+					d.WriteByte(',')
+				}
+				if err := d.value(advance(states, pathElem{index: i})); err != nil {
+					return unexpectedEOF(err)
+				}
+			}
+			d.WriteByte(']')
+		}
+		// Consume the closing delimiter.
+		_, err := d.dec.Token()
+		return unexpectedEOF(err)
+	case string:
+		if d.redacted && o != overrideSafe {
+			d.secret()
+		} else {
+			d.stringLeaf(tok, o)
+		}
+	case json.Number:
+		if d.redacted && o != overrideSafe {
+			d.WriteString(string(d.sentinel))
+		} else {
+			d.numberLeaf(string(tok), o, false)
+		}
+	case bool:
+		d.WriteString(strconv.FormatBool(tok))
+	case nil:
+		d.WriteString("null")
+	}
+	return nil
+}
+
+// unexpectedEOF converts io.EOF, which is only expected between the
+// values of the document, to io.ErrUnexpectedEOF.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// selectorStep is a step of a selector.
+type selectorStep struct {
+	// descendant is set for the .. steps, which match any number of
+	// path elements. The other fields are not used.
+	descendant bool
+	// any is set for the * steps, which match any path element.
+	any bool
+	// name is the object member matched by the step, if index is -1.
+	name string
+	// index is the array element matched by the step.
+	index int
+}
+
+// pathElem is an element of the path leading to a value: an object
+// member name, or an array index if index is not -1.
+type pathElem struct {
+	name  string
+	index int
+}
+
+func (s selectorStep) matches(e pathElem) bool {
+	return s.any || s.index == e.index && (s.index >= 0 || s.name == e.name)
+}
+
+// selectorState is a position reached in a selector.
+type selectorState struct {
+	sel []selectorStep
+	pos int
+}
+
+// selected returns true if one of the states has reached the end of
+// its selector.
+func selected(states []selectorState) bool {
+	for _, s := range states {
+		if s.pos == len(s.sel) {
+			return true
+		}
+	}
+	return false
+}
+
+// advance returns the states reached from states by the path element
+// e.
+func advance(states []selectorState, e pathElem) []selectorState {
+	var next []selectorState
+	for _, s := range states {
+		if s.pos == len(s.sel) {
+			// The value is selected with all its contents.
+			next = append(next, s)
+			continue
+		}
+		step := s.sel[s.pos]
+		if step.descendant {
+			// The descendant step either consumes e, or is skipped.
+			next = append(next, s)
+			step = s.sel[s.pos+1]
+			s.pos++
+		}
+		if step.matches(e) {
+			next = append(next, selectorState{sel: s.sel, pos: s.pos + 1})
+		}
+	}
+	return next
+}
+
+// parseSelector parses a selector of DocumentOptions.Safe.
+func parseSelector(s string) ([]selectorStep, error) {
+	invalid := func(reason string) error {
+		return fmt.Errorf("rjson: invalid selector %q: %s", s, reason)
+	}
+	if !strings.HasPrefix(s, "$") {
+		return nil, invalid("must start with $")
+	}
+	var steps []selectorStep
+	for rest := s[1:]; rest != ""; {
+		var step selectorStep
+		var err error
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			steps = append(steps, selectorStep{descendant: true})
+			rest = rest[2:]
+			if strings.HasPrefix(rest, "[") {
+				step, rest, err = parseBracket(rest)
+			} else {
+				step, rest = parseName(rest)
+			}
+		case strings.HasPrefix(rest, "."):
+			step, rest = parseName(rest[1:])
+		case strings.HasPrefix(rest, "["):
+			step, rest, err = parseBracket(rest)
+		default:
+			return nil, invalid(fmt.Sprintf("unexpected %q", rest))
+		}
+		if err != nil {
+			return nil, invalid(err.Error())
+		}
+		if !step.any && step.index < 0 && step.name == "" {
+			return nil, invalid("empty member name")
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// parseName parses a member name following a dot.
+func parseName(s string) (selectorStep, string) {
+	n := strings.IndexAny(s, ".[")
+	if n < 0 {
+		n = len(s)
+	}
+	if s[:n] == "*" {
+		return selectorStep{any: true}, s[n:]
+	}
+	return selectorStep{name: s[:n], index: -1}, s[n:]
+}
+
+// parseBracket parses a bracketed step: ['name'], ["name"], [3] or
+// [*].
+func parseBracket(s string) (selectorStep, string, error) {
+	if len(s) < 2 {
+		return selectorStep{}, "", errors.New("missing ]")
+	}
+	if q := s[1:2]; q == "'" || q == `"` {
+		end := strings.Index(s[2:], q+"]")
+		if end < 0 {
+			return selectorStep{}, "", errors.New("unterminated member name")
+		}
+		return selectorStep{name: s[2 : 2+end], index: -1}, s[2+end+2:], nil
+	}
+	n := strings.IndexByte(s, ']')
+	if n < 0 {
+		return selectorStep{}, "", errors.New("missing ]")
+	}
+	inner := s[1:n]
+	if inner == "*" {
+		return selectorStep{any: true}, s[n+1:], nil
+	}
+	i, err := strconv.Atoi(inner)
+	if err != nil || i < 0 {
+		return selectorStep{}, "", fmt.Errorf("invalid index %q", inner)
+	}
+	return selectorStep{index: i}, s[n+1:], nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rjson
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/redact"
+)
+
+const testDocument = `{
+  "kind": "job",
+  "id": 42,
+  "owner": {"name": "alice", "uid": 1000},
+  "items": [{"id": 1, "path": "/home/alice"}, {"id": 2, "path": "/tmp"}],
+  "meta": {"version": "v1", "tags": ["a", "b"], "ok": true, "none": null},
+  "weird key": "x"
+}
+{"kind": "job", "id": 43}
+`
+
+func TestDocument(t *testing.T) {
+	opts := DocumentOptions{
+		Safe: []string{"$.kind", "$.items[*].id", "$.meta", "$['weird key']", "$..uid"},
+	}
+	const expAnnotated = `{"kind":"job","id":"‹42›","owner":{"name":"‹alice›","uid":1000},` +
+		`"items":[{"id":1,"path":"‹/home/alice›"},{"id":2,"path":"‹/tmp›"}],` +
+		`"meta":{"version":"v1","tags":["a","b"],"ok":true,"none":null},"weird key":"x"}` + "\n" +
+		`{"kind":"job","id":"‹43›"}` + "\n"
+	var b strings.Builder
+	if err := AnnotateDocument(&b, strings.NewReader(testDocument), opts); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != expAnnotated {
+		t.Errorf("expected:\n%s\ngot:\n%s", expAnnotated, b.String())
+	}
+	annotated := redact.RedactableString(b.String())
+	for _, doc := range strings.Split(strings.TrimSpace(string(annotated.Redact())), "\n") {
+		if !json.Valid([]byte(doc)) {
+			t.Errorf("invalid JSON: %s", doc)
+		}
+	}
+
+	opts.NumberSentinel = "-1"
+	const expRedacted = `{"kind":"job","id":-1,"owner":{"name":"‹×›","uid":1000},` +
+		`"items":[{"id":1,"path":"‹×›"},{"id":2,"path":"‹×›"}],` +
+		`"meta":{"version":"v1","tags":["a","b"],"ok":true,"none":null},"weird key":"x"}` + "\n" +
+		`{"kind":"job","id":-1}` + "\n"
+	b.Reset()
+	if err := RedactDocument(&b, strings.NewReader(testDocument), opts); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != expRedacted {
+		t.Errorf("expected:\n%s\ngot:\n%s", expRedacted, b.String())
+	}
+
+	b.Reset()
+	if err := RedactDocument(&b, strings.NewReader(`[1, "a"]`), DocumentOptions{Safe: []string{"$"}}); err != nil {
+		t.Fatal(err)
+	}
+	if exp := "[1,\"a\"]\n"; b.String() != exp {
+		t.Errorf("expected %q, got %q", exp, b.String())
+	}
+}
+
+func TestDocumentErrors(t *testing.T) {
+	for _, tc := range []struct {
+		doc  string
+		opts DocumentOptions
+	}{
+		{`{"a": [1, 2`, DocumentOptions{}},
+		{`{"a": 1}`, DocumentOptions{Safe: []string{"a"}}},
+		{`{"a": 1}`, DocumentOptions{Safe: []string{"$.a["}}},
+		{`{"a": 1}`, DocumentOptions{Safe: []string{"$.a[x]"}}},
+		{`{"a": 1}`, DocumentOptions{Safe: []string{"$.."}}},
+		{`{"a": 1}`, DocumentOptions{NumberSentinel: "zero"}},
+		{`{"a": 1}`, DocumentOptions{NumberSentinel: "NaN"}},
+		{`{"a": 1}`, DocumentOptions{NumberSentinel: "0x10"}},
+		{`{"a": 1}`, DocumentOptions{NumberSentinel: "+1"}},
+		{`{"a": 1}`, DocumentOptions{NumberSentinel: ".5"}},
+		{`{"a": 1}`, DocumentOptions{NumberSentinel: " 1"}},
+		{`{"a": 1}`, DocumentOptions{NumberSentinel: `"1"`}},
+	} {
+		var b strings.Builder
+		if err := RedactDocument(&b, strings.NewReader(tc.doc), tc.opts); err == nil {
+			t.Errorf("%s %+v: expected error", tc.doc, tc.opts)
+		}
+	}
+}
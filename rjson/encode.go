@@ -0,0 +1,544 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package rjson encodes values as redactable JSON documents.
+//
+// The values are encoded like encoding/json does, but the string and
+// number leaves are enclosed in redaction markers unless they are
+// safe. A leaf is safe when its type implements SafeValue or is
+// registered with RegisterSafeType, when it is inside a value wrapped
+// by redact.Safe, or when it is inside a struct field tagged
+// `redact:"safe"`. The types implementing HashValue are marked for
+// hashing. The object keys, the booleans and null are safe.
+//
+// The markers are always placed inside JSON strings: the unsafe
+// numbers are encoded as strings, like with the ",string" option of
+// encoding/json. The documents thus remain valid JSON before and after
+// Redact and StripMarkers, e.g.:
+//
+//	{"user":"‹alice›","port":"‹5432›","tls":true}
+//	{"user":"‹×›","port":"‹×›","tls":true}
+//	{"user":"alice","port":"5432","tls":true}
+//
+// The redactable strings are encoded as JSON strings with their
+// markers preserved.
+//
+// The values of the types implementing SecretValue and of the struct
+// fields tagged `redact:"secret"` are not encoded at all: they are
+// replaced by the redacted marker "‹×›".
+//
+// The existing JSON documents, which are not produced by Marshal, can
+// be processed by AnnotateDocument and RedactDocument with an
+// allowlist of the safe values.
+package rjson
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/cockroachdb/redact"
+	m "github.com/cockroachdb/redact/internal/markers"
+	w "github.com/cockroachdb/redact/internal/redact"
+	ifmt "github.com/cockroachdb/redact/internal/rfmt"
+)
+
+// Marshal returns the redactable JSON encoding of v. See the package
+// documentation for the placement of the redaction markers, and
+// encoding/json.Marshal for the encoding.
+func Marshal(v interface{}) (redact.RedactableBytes, error) {
+	e := encodeState{visiting: map[interface{}]bool{}}
+	if err := e.value(reflect.ValueOf(v), noOverride, false); err != nil {
+		return nil, err
+	}
+	return redact.RedactableBytes(e.Bytes()), nil
+}
+
+// MarshalIndent is like Marshal but applies json.Indent to format the
+// output.
+func MarshalIndent(v interface{}, prefix, indent string) (redact.RedactableBytes, error) {
+	b, err := Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, b, prefix, indent); err != nil {
+		return nil, err
+	}
+	return redact.RedactableBytes(buf.Bytes()), nil
+}
+
+// An Encoder writes redactable JSON values to an output stream.
+type Encoder struct {
+	w              io.Writer
+	prefix, indent string
+}
+
+// NewEncoder returns a new encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// SetIndent instructs the encoder to format each subsequent encoded
+// value as if indented by MarshalIndent.
+func (enc *Encoder) SetIndent(prefix, indent string) {
+	enc.prefix, enc.indent = prefix, indent
+}
+
+// Encode writes the redactable JSON encoding of v to the stream,
+// followed by a newline character.
+func (enc *Encoder) Encode(v interface{}) error {
+	var b redact.RedactableBytes
+	var err error
+	if enc.prefix != "" || enc.indent != "" {
+		b, err = MarshalIndent(v, enc.prefix, enc.indent)
+	} else {
+		b, err = Marshal(v)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = enc.w.Write(append(b, '\n'))
+	return err
+}
+
+// override is the rendering of the leaves imposed by an enclosing
+// value.
+type override int
+
+const (
+	noOverride override = iota
+	// The leaves are safe.
+	overrideSafe
+	// The leaves are unsafe, even if their type is safe.
+	overrideUnsafe
+	// The leaves are marked for hashing.
+	overrideHash
+)
+
+var (
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	safeValueType     = reflect.TypeOf((*redact.SafeValue)(nil)).Elem()
+	hashValueType     = reflect.TypeOf((*redact.HashValue)(nil)).Elem()
+	secretValueType   = reflect.TypeOf((*redact.SecretValue)(nil)).Elem()
+	safeWrapperType   = reflect.TypeOf(w.SafeWrapper{})
+	unsafeWrapperType = reflect.TypeOf(w.UnsafeWrap{})
+
+	redactableStringType = reflect.TypeOf(redact.RedactableString(""))
+	redactableBytesType  = reflect.TypeOf(redact.RedactableBytes(nil))
+)
+
+// typeOverride returns the override applying to the values of type t
+// inside a value with the override o.
+func typeOverride(t reflect.Type, o override) override {
+	if o != noOverride {
+		return o
+	}
+	switch {
+	case t.Implements(safeValueType) || ifmt.IsSafeType(t):
+		return overrideSafe
+	case t.Implements(hashValueType):
+		return overrideHash
+	}
+	return noOverride
+}
+
+// encodeState accumulates the output of Marshal.
+type encodeState struct {
+	bytes.Buffer
+	// visiting are the pointers and maps being encoded, to detect
+	// cycles.
+	visiting map[interface{}]bool
+}
+
+// value encodes v. quoted is set for the struct fields with the
+// ",string" option.
+func (e *encodeState) value(v reflect.Value, o override, quoted bool) error {
+	if !v.IsValid() {
+		e.WriteString("null")
+		return nil
+	}
+	t := v.Type()
+	switch t {
+	case safeWrapperType:
+		if o == noOverride {
+			o = overrideSafe
+		}
+		return e.value(reflect.ValueOf(v.Interface().(w.SafeWrapper).GetValue()), o, quoted)
+	case unsafeWrapperType:
+		if o == noOverride {
+			o = overrideUnsafe
+		}
+		return e.value(reflect.ValueOf(v.Interface().(w.UnsafeWrap).GetValue()), o, quoted)
+	}
+	if t.Implements(secretValueType) {
+		e.secret()
+		return nil
+	}
+	o = typeOverride(t, o)
+
+	if (t == redactableStringType || t == redactableBytesType) && o != overrideUnsafe {
+		// The markers of the redactable strings are preserved.
+		var s string
+		if t == redactableStringType {
+			s = v.String()
+		} else {
+			s = string(v.Bytes())
+		}
+		b, _ := json.Marshal(s)
+		e.Write(b)
+		return nil
+	}
+
+	if t.Kind() != reflect.Ptr && v.CanAddr() && reflect.PtrTo(t).Implements(marshalerType) {
+		v = v.Addr()
+	}
+	if v.Type().Implements(marshalerType) {
+		if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+			e.WriteString("null")
+			return nil
+		}
+		b, err := v.Interface().(json.Marshaler).MarshalJSON()
+		if err != nil {
+			return &json.MarshalerError{Type: t, Err: err}
+		}
+		if err := e.raw(json.NewDecoder(bytes.NewReader(b)), o); err != nil {
+			return &json.MarshalerError{Type: t, Err: err}
+		}
+		return nil
+	}
+	if t.Kind() != reflect.Ptr && v.CanAddr() && reflect.PtrTo(t).Implements(textMarshalerType) {
+		v = v.Addr()
+	}
+	if v.Type().Implements(textMarshalerType) {
+		if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+			e.WriteString("null")
+			return nil
+		}
+		b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return &json.MarshalerError{Type: t, Err: err}
+		}
+		e.stringLeaf(string(b), o)
+		return nil
+	}
+	return e.kind(v, o, quoted)
+}
+
+// kind encodes v according to its kind.
+func (e *encodeState) kind(v reflect.Value, o override, quoted bool) error {
+	t := v.Type()
+	switch v.Kind() {
+	case reflect.Bool:
+		if quoted {
+			e.WriteByte('"')
+			e.WriteString(strconv.FormatBool(v.Bool()))
+			e.WriteByte('"')
+		} else {
+			e.WriteString(strconv.FormatBool(v.Bool()))
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.numberLeaf(strconv.FormatInt(v.Int(), 10), o, quoted)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.numberLeaf(strconv.FormatUint(v.Uint(), 10), o, quoted)
+
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return &json.UnsupportedValueError{Value: v, Str: strconv.FormatFloat(f, 'g', -1, 64)}
+		}
+		var b []byte
+		if t.Kind() == reflect.Float32 {
+			b, _ = json.Marshal(float32(f))
+		} else {
+			b, _ = json.Marshal(f)
+		}
+		e.numberLeaf(string(b), o, quoted)
+
+	case reflect.String:
+		s := v.String()
+		if quoted {
+			b, _ := json.Marshal(s)
+			s = string(b)
+		}
+		e.stringLeaf(s, o)
+
+	case reflect.Struct:
+		return e.structValue(v, o)
+
+	case reflect.Map:
+		return e.mapValue(v, o)
+
+	case reflect.Slice:
+		if v.IsNil() {
+			e.WriteString("null")
+			return nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 && !t.Elem().Implements(marshalerType) &&
+			!reflect.PtrTo(t.Elem()).Implements(marshalerType) &&
+			!t.Elem().Implements(textMarshalerType) && !reflect.PtrTo(t.Elem()).Implements(textMarshalerType) {
+			e.stringLeaf(base64.StdEncoding.EncodeToString(v.Bytes()), o)
+			return nil
+		}
+		if !e.enter(v) {
+			return &json.UnsupportedValueError{Value: v, Str: "encountered a cycle via " + t.String()}
+		}
+		defer e.leave(v)
+		return e.array(v, o)
+
+	case reflect.Array:
+		return e.array(v, o)
+
+	case reflect.Interface:
+		if v.IsNil() {
+			e.WriteString("null")
+			return nil
+		}
+		return e.value(v.Elem(), o, false)
+
+	case reflect.Ptr:
+		if v.IsNil() {
+			e.WriteString("null")
+			return nil
+		}
+		if !e.enter(v) {
+			return &json.UnsupportedValueError{Value: v, Str: "encountered a cycle via " + t.String()}
+		}
+		defer e.leave(v)
+		return e.value(v.Elem(), o, quoted)
+
+	default:
+		return &json.UnsupportedTypeError{Type: t}
+	}
+	return nil
+}
+
+func (e *encodeState) structValue(v reflect.Value, o override) error {
+	e.WriteByte('{')
+	first := true
+fields:
+	for _, f := range cachedFields(v.Type()) {
+		fv := v
+		for _, i := range f.index {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue fields
+				}
+				fv = fv.Elem()
+			}
+			fv = fv.Field(i)
+		}
+		if f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		if !first {
+			e.WriteByte(',')
+		}
+		first = false
+		e.key(f.name)
+		switch {
+		case f.secret:
+			e.secret()
+		case f.safe && o == noOverride:
+			if err := e.value(fv, overrideSafe, f.quoted); err != nil {
+				return err
+			}
+		default:
+			if err := e.value(fv, o, f.quoted); err != nil {
+				return err
+			}
+		}
+	}
+	e.WriteByte('}')
+	return nil
+}
+
+func (e *encodeState) mapValue(v reflect.Value, o override) error {
+	if v.IsNil() {
+		e.WriteString("null")
+		return nil
+	}
+	if !e.enter(v) {
+		return &json.UnsupportedValueError{Value: v, Str: "encountered a cycle via " + v.Type().String()}
+	}
+	defer e.leave(v)
+
+	type keyValue struct {
+		key string
+		v   reflect.Value
+	}
+	kvs := make([]keyValue, 0, v.Len())
+	for it := v.MapRange(); it.Next(); {
+		k, err := mapKey(it.Key())
+		if err != nil {
+			return err
+		}
+		kvs = append(kvs, keyValue{k, it.Value()})
+	}
+	sort.Slice(kvs, func(i, j int) bool { return kvs[i].key < kvs[j].key })
+
+	e.WriteByte('{')
+	for i, kv := range kvs {
+		if i > 0 {
+			e.WriteByte(',')
+		}
+		e.key(kv.key)
+		if err := e.value(kv.v, o, false); err != nil {
+			return err
+		}
+	}
+	e.WriteByte('}')
+	return nil
+}
+
+// mapKey returns the object key for the map key k, like
+// encoding/json.
+func mapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Ptr && k.IsNil() {
+			return "", nil
+		}
+		b, err := tm.MarshalText()
+		return string(b), err
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	return "", &json.UnsupportedTypeError{Type: k.Type()}
+}
+
+func (e *encodeState) array(v reflect.Value, o override) error {
+	e.WriteByte('[')
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			e.WriteByte(',')
+		}
+		if err := e.value(v.Index(i), o, false); err != nil {
+			return err
+		}
+	}
+	e.WriteByte(']')
+	return nil
+}
+
+// key writes an object key, which is safe, and the colon following
+// it.
+func (e *encodeState) key(k string) {
+	b, _ := json.Marshal(k)
+	e.Write(m.EscapeMarkers(b))
+	e.WriteByte(':')
+}
+
+// stringLeaf writes the string s.
+func (e *encodeState) stringLeaf(s string, o override) {
+	b, _ := json.Marshal(s)
+	e.quotedLeaf(b[1:len(b)-1], o)
+}
+
+// numberLeaf writes the JSON number num, as a string if it is not
+// safe or if quoted is set.
+func (e *encodeState) numberLeaf(num string, o override, quoted bool) {
+	if o == overrideSafe && !quoted {
+		e.WriteString(num)
+		return
+	}
+	e.quotedLeaf([]byte(num), o)
+}
+
+// quotedLeaf writes a JSON string with the already escaped contents
+// s, enclosed in redaction markers if it is not safe.
+func (e *encodeState) quotedLeaf(s []byte, o override) {
+	e.WriteByte('"')
+	s = m.EscapeMarkers(s)
+	switch o {
+	case overrideSafe:
+		e.Write(s)
+	case overrideHash:
+		e.WriteString(m.StartS)
+		e.WriteString(m.HashPrefixS)
+		e.Write(s)
+		e.WriteString(m.EndS)
+	default:
+		e.WriteString(m.StartS)
+		e.Write(s)
+		e.WriteString(m.EndS)
+	}
+	e.WriteByte('"')
+}
+
+// secret writes the placeholder of a secret value.
+func (e *encodeState) secret() {
+	e.WriteByte('"')
+	e.WriteString(m.RedactedS)
+	e.WriteByte('"')
+}
+
+// enter marks the pointer, map or slice v as being encoded. It returns
+// false if v is already being encoded.
+func (e *encodeState) enter(v reflect.Value) bool {
+	k := visitKey(v)
+	if e.visiting[k] {
+		return false
+	}
+	e.visiting[k] = true
+	return true
+}
+
+func (e *encodeState) leave(v reflect.Value) { delete(e.visiting, visitKey(v)) }
+
+// visitKey identifies a pointer, map or slice for the detection of
+// cycles. The length distinguishes a slice from its sub-slices.
+func visitKey(v reflect.Value) interface{} {
+	type key struct {
+		ptr uintptr
+		len int
+	}
+	if v.Kind() == reflect.Slice {
+		return key{v.Pointer(), v.Len()}
+	}
+	return key{v.Pointer(), -1}
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rjson
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// field is a struct field encoded as an object member.
+type field struct {
+	name string
+	// index is the sequence of field indexes leading to the field
+	// through the embedded structs.
+	index []int
+	// tagged is set when the name comes from the json tag.
+	tagged    bool
+	omitEmpty bool
+	quoted    bool
+	// safe and secret are set by the redact tag.
+	safe   bool
+	secret bool
+}
+
+var fieldCache sync.Map // map[reflect.Type][]field
+
+// cachedFields returns the encoded fields of the struct type t.
+func cachedFields(t reflect.Type) []field {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.([]field)
+	}
+	f, _ := fieldCache.LoadOrStore(t, typeFields(t))
+	return f.([]field)
+}
+
+// typeFields returns the encoded fields of the struct type t, with the
+// rules of encoding/json: the fields of the embedded structs are
+// promoted, and among the fields with the same name, the shallowest
+// one wins if it is unique, or the tagged one if it is the only tagged
+// one at that depth; the others are ignored.
+func typeFields(t reflect.Type) []field {
+	var fields []field
+	collectFields(t, nil, map[reflect.Type]bool{}, &fields)
+
+	// Group the fields by name, the dominant one first.
+	sort.SliceStable(fields, func(i, j int) bool {
+		fi, fj := fields[i], fields[j]
+		if fi.name != fj.name {
+			return fi.name < fj.name
+		}
+		if len(fi.index) != len(fj.index) {
+			return len(fi.index) < len(fj.index)
+		}
+		return fi.tagged && !fj.tagged
+	})
+	res := fields[:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		group := fields[i:j]
+		if len(group) == 1 || len(group[1].index) > len(group[0].index) ||
+			group[0].tagged && !group[1].tagged {
+			res = append(res, group[0])
+		}
+		i = j
+	}
+
+	// Restore the declaration order.
+	sort.Slice(res, func(i, j int) bool {
+		a, b := res[i].index, res[j].index
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return res
+}
+
+// collectFields appends the fields of t, found at index, to fields.
+func collectFields(t reflect.Type, index []int, visited map[reflect.Type]bool, fields *[]field) {
+	if visited[t] {
+		return
+	}
+	visited[t] = true
+	defer delete(visited, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		ft := sf.Type
+		if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous {
+			if !sf.IsExported() && ft.Kind() != reflect.Struct {
+				continue
+			}
+		} else if !sf.IsExported() {
+			continue
+		}
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		idx := append(append([]int(nil), index...), i)
+		if name == "" && sf.Anonymous && ft.Kind() == reflect.Struct {
+			collectFields(ft, idx, visited, fields)
+			continue
+		}
+		f := field{name: name, index: idx, tagged: name != ""}
+		if name == "" {
+			f.name = sf.Name
+		}
+		for opts != "" {
+			var opt string
+			opt, opts, _ = strings.Cut(opts, ",")
+			switch opt {
+			case "omitempty":
+				f.omitEmpty = true
+			case "string":
+				switch ft.Kind() {
+				case reflect.Bool, reflect.String,
+					reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+					reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+					reflect.Float32, reflect.Float64:
+					f.quoted = true
+				}
+			}
+		}
+		rtag, _, _ := strings.Cut(sf.Tag.Get("redact"), ",")
+		f.safe = rtag == "safe"
+		f.secret = rtag == "secret"
+		*fields = append(*fields, f)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rjson
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// raw re-encodes the JSON value read from dec, e.g. the output of a
+// json.Marshaler, with the leaves rendered according to o. The order
+// of the object keys is preserved.
+func (e *encodeState) raw(dec *json.Decoder, o override) error {
+	dec.UseNumber()
+	if err := e.rawValue(dec, o); err != nil {
+		return err
+	}
+	if dec.More() {
+		return fmt.Errorf("invalid character after top-level value")
+	}
+	return nil
+}
+
+func (e *encodeState) rawValue(dec *json.Decoder, o override) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok := tok.(type) {
+	case json.Delim:
+		switch tok {
+		case '{':
+			e.WriteByte('{')
+			for first := true; dec.More(); first = false {
+				if !first {
+					e.WriteByte(',')
+				}
+				k, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				e.key(k.(string))
+				if err := e.rawValue(dec, o); err != nil {
+					return err
+				}
+			}
+			e.WriteByte('}')
+		case '[':
+			e.WriteByte('[')
+			for first := true; dec.More(); first = false {
+				if !first {
+					e.WriteByte(',')
+				}
+				if err := e.rawValue(dec, o); err != nil {
+					return err
+				}
+			}
+			e.WriteByte(']')
+		}
+		// Consume the closing delimiter.
+		_, err := dec.Token()
+		return err
+	case string:
+		e.stringLeaf(tok, o)
+	case json.Number:
+		e.numberLeaf(string(tok), o, false)
+	case bool:
+		fmt.Fprint(e, tok)
+	case nil:
+		e.WriteString("null")
+	}
+	return nil
+}
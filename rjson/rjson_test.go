@@ -0,0 +1,133 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rjson
+
+import (
+	"encoding/json"
+	"math"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/redact"
+)
+
+type testRegion string
+
+func init() {
+	redact.RegisterSafeType(reflect.TypeOf(testRegion("")))
+}
+
+type testBase struct {
+	ID      redact.SafeInt `json:"id"`
+	Created time.Time      `json:"created"`
+}
+
+type testUser struct {
+	testBase
+	Name     string                `json:"name"`
+	Email    redact.HashString     `json:"email"`
+	Age      int                   `json:"age,omitempty"`
+	Score    float64               `json:"score,string"`
+	Admin    bool                  `json:"admin"`
+	Region   testRegion            `json:"region"`
+	Role     string                `json:"role" redact:"safe"`
+	Password string                `json:"password" redact:"secret"`
+	Token    redact.Secret[string] `json:"token"`
+	IP       net.IP                `json:"ip"`
+	Labels   map[string]string     `json:"labels"`
+	Raw      json.RawMessage       `json:"raw"`
+	Extra    interface{}           `json:"extra"`
+	Skipped  string                `json:"-"`
+	internal string
+}
+
+func TestMarshal(t *testing.T) {
+	u := testUser{
+		testBase: testBase{ID: 7, Created: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)},
+		Name:     `al"ice‹`,
+		Email:    "alice@example.com",
+		Score:    1.5,
+		Admin:    true,
+		Region:   "eu",
+		Role:     "admin",
+		Password: "hunter2",
+		Token:    redact.MakeSecret("s3cr3t"),
+		IP:       net.IPv4(10, 0, 0, 1),
+		Labels:   map[string]string{"b": "2", "a‹": "1"},
+		Raw:      json.RawMessage(`{"z":1,"a":[true,null,"x"]}`),
+		Extra:    []interface{}{redact.Safe(map[string]int{"n": 3}), redact.Unsafe(redact.SafeInt(4))},
+		Skipped:  "skipped",
+		internal: "internal",
+	}
+	const exp = `{"id":7,"created":"‹2020-01-02T03:04:05Z›","name":"‹al\"ice?›",` +
+		`"email":"‹†alice@example.com›","score":"‹1.5›","admin":true,"region":"eu",` +
+		`"role":"admin","password":"‹×›","token":"‹×›","ip":"‹10.0.0.1›",` +
+		`"labels":{"a?":"‹1›","b":"‹2›"},"raw":{"z":"‹1›","a":[true,null,"‹x›"]},` +
+		`"extra":[{"n":3},"‹4›"]}`
+	actual, err := Marshal(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(actual) != exp {
+		t.Errorf("expected:\n%s\ngot:\n%s", exp, actual)
+	}
+	for _, doc := range []string{string(actual), string(actual.Redact()), string(actual.StripMarkers())} {
+		if !json.Valid([]byte(doc)) {
+			t.Errorf("invalid JSON: %s", doc)
+		}
+	}
+	if s := string(actual.StripMarkers()); strings.Contains(s, "hunter2") || strings.Contains(s, "s3cr3t") {
+		t.Errorf("secret encoded: %s", s)
+	}
+}
+
+func TestMarshalIndent(t *testing.T) {
+	actual, err := MarshalIndent(map[string]interface{}{"a": []int{1}, "b": nil}, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const exp = "{\n  \"a\": [\n    \"‹1›\"\n  ],\n  \"b\": null\n}"
+	if string(actual) != exp {
+		t.Errorf("expected:\n%s\ngot:\n%s", exp, actual)
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	type cycle struct{ Next *cycle }
+	c := &cycle{}
+	c.Next = c
+	for _, v := range []interface{}{math.NaN(), make(chan int), c} {
+		if _, err := Marshal(v); err == nil {
+			t.Errorf("%T: expected error", v)
+		}
+	}
+}
+
+func TestMarshalRedactable(t *testing.T) {
+	actual, err := Marshal(map[string]interface{}{
+		"msg":    redact.Sprintf("user %s", "alice"),
+		"unsafe": redact.Unsafe(redact.Sprintf("user %s", "alice")),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const exp = `{"msg":"user ‹alice›","unsafe":"‹user ?alice?›"}`
+	if string(actual) != exp {
+		t.Errorf("expected:\n%s\ngot:\n%s", exp, actual)
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package rpanic renders panic values and goroutine stack dumps as
+// redactable strings, for crash reports.
+//
+// In the stack dumps produced by runtime.Stack and debug.Stack, the
+// goroutine headers, the function names and the file:line positions
+// are safe. The argument words of the frames are unsafe, as well as
+// the lines that are not recognized, so that a malformed dump does not
+// leak data.
+//
+// Rendering the positions as safe is a policy choice: crash reports
+// are hardly usable without them. Note that the file paths are those
+// of the build environment, e.g. /home/alice/src/main.go, and can
+// reveal user or directory names of that environment. Build with
+// -trimpath to keep them out of the reports.
+package rpanic
+
+import (
+	"bytes"
+	"runtime/debug"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/redact"
+)
+
+// Report renders a panic value and the stack dump of the panicking
+// goroutine like the Go runtime does when a program crashes:
+//
+//	panic: <value>
+//
+//	goroutine 1 [running]:
+//	...
+//
+// The value is printed by redact.Sprint, so that the values
+// implementing SafeFormatter remain partially safe.
+func Report(value interface{}, stack []byte) redact.RedactableString {
+	var b redact.StringBuilder
+	b.Printf("panic: %v\n\n", value)
+	b.Print(Stack(stack))
+	return b.RedactableString()
+}
+
+// Recover recovers from a panic, if any, and calls report with the
+// rendering of the panic value and of the current stack. It must be
+// called directly by a deferred call:
+//
+//	defer rpanic.Recover(report)
+//
+// The panic is not propagated further. The report function can exit
+// the process after the report is stored.
+func Recover(report func(redact.RedactableString)) {
+	if r := recover(); r != nil {
+		report(Report(r, debug.Stack()))
+	}
+}
+
+// Go runs f in a new goroutine, which reports its panics, if any, with
+// report. See Recover.
+func Go(f func(), report func(redact.RedactableString)) {
+	go func() {
+		defer Recover(report)
+		f()
+	}()
+}
+
+// Stack renders a goroutine stack dump.
+func Stack(stack []byte) redact.RedactableString {
+	var b redact.StringBuilder
+	lines := bytes.Split(stack, []byte("\n"))
+	for i, line := range lines {
+		if i > 0 {
+			b.SafeRune('\n')
+		}
+		writeLine(&b, string(line))
+	}
+	return b.RedactableString()
+}
+
+// writeLine renders one line of a stack dump.
+func writeLine(b *redact.StringBuilder, line string) {
+	switch {
+	case line == "",
+		isPosition(line),
+		isHeader(line),
+		isCreatedBy(line),
+		line == "...additional frames elided...":
+		// Empty lines, file:line positions, goroutine headers and
+		// goroutine creators.
+		b.SafeString(redact.SafeString(line))
+		return
+	}
+	// Function calls, e.g. main.(*T).f(0xc000012345, {0x1, 0x2}).
+	if open := argsStart(line); open > 0 && isFuncName(line[:open]) {
+		b.SafeString(redact.SafeString(line[:open+1]))
+		writeArgs(b, line[open+1:len(line)-1])
+		b.SafeRune(')')
+		return
+	}
+	b.UnsafeString(line)
+}
+
+// isPosition returns true if line is the position of a frame, of the
+// form "\t<path>:<line> +0x<offset>".
+func isPosition(line string) bool {
+	if !strings.HasPrefix(line, "\t") {
+		return false
+	}
+	off := strings.LastIndex(line, " +0x")
+	if off == -1 || !isDigits(line[off+len(" +0x"):], 16) {
+		return false
+	}
+	colon := strings.LastIndexByte(line[:off], ':')
+	return colon > len("\t") && isDigits(line[colon+1:off], 10)
+}
+
+// isHeader returns true if line is a goroutine header, of the form
+// "goroutine <id> [<state>]:", e.g. "goroutine 7 [chan receive, 2
+// minutes]:".
+func isHeader(line string) bool {
+	rest, ok := cutPrefix(line, "goroutine ")
+	if !ok {
+		return false
+	}
+	sp := strings.IndexByte(rest, ' ')
+	if sp == -1 || !isDigits(rest[:sp], 10) {
+		return false
+	}
+	state, ok := cutPrefix(rest[sp+1:], "[")
+	if !ok || !strings.HasSuffix(state, "]:") {
+		return false
+	}
+	state = state[:len(state)-len("]:")]
+	for _, r := range state {
+		if !(isIdentRune(r) || strings.ContainsRune(" ,.()", r)) {
+			return false
+		}
+	}
+	return state != ""
+}
+
+// isCreatedBy returns true if line names the creator of a goroutine,
+// of the form "created by <func> in goroutine <id>", or "created by
+// <func>" before Go 1.21.
+func isCreatedBy(line string) bool {
+	rest, ok := cutPrefix(line, "created by ")
+	if !ok {
+		return false
+	}
+	if i := strings.Index(rest, " in goroutine "); i != -1 {
+		if !isDigits(rest[i+len(" in goroutine "):], 10) {
+			return false
+		}
+		rest = rest[:i]
+	}
+	return isFuncName(rest)
+}
+
+// isFuncName returns true if s is a function name as printed in the
+// stack dumps, e.g. example.com/go-pkg.(*T[...]).f.func1. The package
+// path may contain slashes, dashes and escaped characters; the rest is
+// made of identifiers, dots, stars, parentheses and the [...] of the
+// generic functions.
+func isFuncName(s string) bool {
+	if s == "" {
+		return false
+	}
+	// The method values are suffixed with -fm.
+	s = strings.TrimSuffix(s, "-fm")
+	if slash := strings.LastIndexByte(s, '/'); slash != -1 {
+		// The package path ends at the first dot of its last element.
+		end := len(s)
+		if dot := strings.IndexByte(s[slash:], '.'); dot != -1 {
+			end = slash + dot
+		}
+		for _, r := range s[:end] {
+			if !(isIdentRune(r) || strings.ContainsRune(".-/~%", r)) {
+				return false
+			}
+		}
+		s = s[end:]
+	}
+	for s != "" {
+		if rest, ok := cutPrefix(s, "[...]"); ok {
+			s = rest
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s)
+		if !(isIdentRune(r) || strings.ContainsRune(".*()", r)) {
+			return false
+		}
+		s = s[size:]
+	}
+	return true
+}
+
+// isIdentRune returns true if r can be part of an identifier.
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// cutPrefix is strings.CutPrefix, which requires Go 1.20.
+func cutPrefix(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
+
+// isDigits returns true if s is a non-empty sequence of digits in the
+// base, which is 10 or 16.
+func isDigits(s string, base int) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !('0' <= c && c <= '9' || base == 16 && 'a' <= c && c <= 'f') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// argsStart returns the position of the parenthesis opening the
+// argument list at the end of line, or -1 if there is none.
+func argsStart(line string) int {
+	if !strings.HasSuffix(line, ")") {
+		return -1
+	}
+	depth := 0
+	for i := len(line) - 1; i >= 0; i-- {
+		switch line[i] {
+		case ')':
+			depth++
+		case '(':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// writeArgs renders an argument list. The words are unsafe, the
+// punctuation is safe.
+func writeArgs(b *redact.StringBuilder, args string) {
+	for len(args) > 0 {
+		if n := strings.IndexAny(args, argsPunct); n != 0 {
+			if n < 0 {
+				n = len(args)
+			}
+			b.UnsafeString(args[:n])
+			args = args[n:]
+			continue
+		}
+		b.SafeRune(redact.SafeRune(args[0]))
+		args = args[1:]
+	}
+}
+
+// argsPunct are the punctuation characters of the argument lists.
+const argsPunct = "{}(), ."
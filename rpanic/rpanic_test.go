@@ -0,0 +1,100 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rpanic
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/redact"
+)
+
+const testStack = `goroutine 7 [running, locked to thread]:
+main.(*Server).handle[...](0xc000012345, {0x4a2b40?, 0xc000010250?}, ...)
+	/home/alice/src/server.go:42 +0x25
+panic({0x4a2b40, 0xc000010250})
+	/usr/local/go/src/runtime/panic.go:770 +0x132
+main.main.func1()
+	/home/alice/src/main.go:10 +0x1d
+created by main.main in goroutine 1
+	/home/alice/src/main.go:9 +0x66
+not a frame: secret
+	tabbed secret
+	/src/main.go:secret +0x1
+	/src/main.go:12 +0xsecret
+goroutine secret-data
+goroutine 8 [running]: secret
+goroutine 9 [secret=1]:
+created by secret stuff
+created by main.main in goroutine secret
+secret=x(y)
+secret-data(y)
+example.com/x-y.f[...](0x1)
+created by example.com/x-y.(*T).f-fm
+`
+
+func TestStack(t *testing.T) {
+	const exp = `goroutine 7 [running, locked to thread]:
+main.(*Server).handle[...](‹0xc000012345›, {‹0x4a2b40?›, ‹0xc000010250?›}, ...)
+	/home/alice/src/server.go:42 +0x25
+panic({‹0x4a2b40›, ‹0xc000010250›})
+	/usr/local/go/src/runtime/panic.go:770 +0x132
+main.main.func1()
+	/home/alice/src/main.go:10 +0x1d
+created by main.main in goroutine 1
+	/home/alice/src/main.go:9 +0x66
+‹not a frame: secret›
+‹	tabbed secret›
+‹	/src/main.go:secret +0x1›
+‹	/src/main.go:12 +0xsecret›
+‹goroutine secret-data›
+‹goroutine 8 [running]: secret›
+‹goroutine 9 [secret=1]:›
+‹created by secret stuff›
+‹created by main.main in goroutine secret›
+‹secret=x(y)›
+‹secret-data(y)›
+example.com/x-y.f[...](‹0x1›)
+created by example.com/x-y.(*T).f-fm
+`
+	if actual := Stack([]byte(testStack)); actual != exp {
+		t.Errorf("expected:\n%s\ngot:\n%s", exp, actual)
+	}
+}
+
+func TestReport(t *testing.T) {
+	const exp = `panic: ‹oops› safe
+
+goroutine 1 [running]:
+main.main()
+	/tmp/main.go:3 +0x1`
+	actual := Report(redact.Sprintf("%s safe", "oops"),
+		[]byte("goroutine 1 [running]:\nmain.main()\n\t/tmp/main.go:3 +0x1"))
+	if actual != exp {
+		t.Errorf("expected:\n%s\ngot:\n%s", exp, actual)
+	}
+}
+
+func TestGo(t *testing.T) {
+	reports := make(chan redact.RedactableString)
+	Go(func() { panic("secret") }, func(r redact.RedactableString) { reports <- r })
+	r := <-reports
+	if !strings.HasPrefix(string(r), "panic: ‹secret›\n\ngoroutine ") {
+		t.Errorf("unexpected report:\n%s", r)
+	}
+	if !strings.Contains(string(r), "rpanic.TestGo.func1()") {
+		t.Errorf("expected the panicking frame, got:\n%s", r)
+	}
+}
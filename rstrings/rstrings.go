@@ -0,0 +1,241 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package rstrings implements functions to manipulate redactable
+// strings, similar to those of the strings package.
+//
+// The functions preserve the redaction markers: the results are always
+// well-formed, and unsafe text never becomes safe. Malformed markers in
+// the arguments are handled as by RedactableString.Repair.
+//
+// Except when stated otherwise, the functions operate on the text of
+// the redactable strings with the markers stripped, so that for
+// example a separator can be found both in safe and unsafe text. When
+// an unsafe region is cut, each part remains unsafe.
+package rstrings
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/redact"
+)
+
+// View selects the text that a function matches against.
+type View int
+
+const (
+	// Stripped is the text of the redactable string with the markers
+	// stripped, including the unsafe text.
+	Stripped View = iota
+	// Redacted is the text of the redacted string with the markers
+	// stripped, where each unsafe region is replaced by ×.
+	Redacted
+)
+
+func (v View) text(s redact.RedactableString) string {
+	if v == Redacted {
+		return s.Redact().StripMarkers()
+	}
+	return s.StripMarkers()
+}
+
+// HasPrefix tests whether the text of s in the given view begins with
+// prefix.
+func HasPrefix(s redact.RedactableString, prefix string, v View) bool {
+	return strings.HasPrefix(v.text(s), prefix)
+}
+
+// Contains reports whether substr is within the text of s in the given
+// view.
+func Contains(s redact.RedactableString, substr string, v View) bool {
+	return strings.Contains(v.text(s), substr)
+}
+
+// Split slices s into all substrings separated by sep and returns a
+// slice of the substrings between those separators. The separators
+// are matched in the stripped text, including in the unsafe regions.
+// If sep is empty, Split splits after each UTF-8 sequence.
+func Split(s redact.RedactableString, sep string) []redact.RedactableString {
+	t := parse(s)
+	var res []redact.RedactableString
+	if sep == "" {
+		for i := 0; i < len(t.text); {
+			_, n := utf8.DecodeRuneInString(t.text[i:])
+			res = append(res, t.slice(i, i+n))
+			i += n
+		}
+		return res
+	}
+	start := 0
+	for {
+		i := strings.Index(t.text[start:], sep)
+		if i == -1 {
+			break
+		}
+		res = append(res, t.slice(start, start+i))
+		start += i + len(sep)
+	}
+	return append(res, t.slice(start, len(t.text)))
+}
+
+// Fields splits s around each instance of one or more consecutive
+// white space characters, as defined by unicode.IsSpace, in the
+// stripped text.
+func Fields(s redact.RedactableString) []redact.RedactableString {
+	t := parse(s)
+	var res []redact.RedactableString
+	start := -1
+	for i, r := range t.text {
+		if unicode.IsSpace(r) {
+			if start != -1 {
+				res = append(res, t.slice(start, i))
+				start = -1
+			}
+		} else if start == -1 {
+			start = i
+		}
+	}
+	if start != -1 {
+		res = append(res, t.slice(start, len(t.text)))
+	}
+	return res
+}
+
+// TrimSpace returns s with all leading and trailing white space
+// removed, as defined by unicode.IsSpace, in the stripped text.
+func TrimSpace(s redact.RedactableString) redact.RedactableString {
+	t := parse(s)
+	trimmed := strings.TrimLeftFunc(t.text, unicode.IsSpace)
+	start := len(t.text) - len(trimmed)
+	end := start + len(strings.TrimRightFunc(trimmed, unicode.IsSpace))
+	return t.slice(start, end)
+}
+
+// Lines returns the lines of s, each including its terminating
+// newline if any.
+func Lines(s redact.RedactableString) []redact.RedactableString {
+	t := parse(s)
+	var res []redact.RedactableString
+	for start := 0; start < len(t.text); {
+		end := len(t.text)
+		if i := strings.IndexByte(t.text[start:], '\n'); i != -1 {
+			end = start + i + 1
+		}
+		res = append(res, t.slice(start, end))
+		start = end
+	}
+	return res
+}
+
+// Replace returns a copy of s with the first n non-overlapping
+// instances of old replaced by new. If n < 0, there is no limit on the
+// number of replacements.
+//
+// Only the safe text is considered: the instances of old in the unsafe
+// regions, or overlapping them, are not replaced. The redaction
+// markers in new are escaped.
+func Replace(s redact.RedactableString, old, new string, n int) redact.RedactableString {
+	spans := s.Spans()
+	new = string(redact.EscapeMarkers([]byte(new)))
+	for i := range spans {
+		if n == 0 {
+			break
+		}
+		if spans[i].Kind != redact.SafeSpan {
+			continue
+		}
+		text := spans[i].Text
+		count := strings.Count(text, old)
+		if n > 0 && count > n {
+			count = n
+		}
+		spans[i].Text = strings.Replace(text, old, new, count)
+		if n > 0 {
+			n -= count
+		}
+	}
+	return build(spans)
+}
+
+// Concat concatenates the redactable strings. The malformed markers in
+// each string are repaired first, so that for example an unclosed
+// unsafe region does not extend into the next string.
+func Concat(ss ...redact.RedactableString) redact.RedactableString {
+	var b strings.Builder
+	for _, s := range ss {
+		b.WriteString(string(s.Repair()))
+	}
+	return redact.RedactableString(b.String())
+}
+
+// text is a redactable string split in spans, along with its stripped
+// text.
+type text struct {
+	spans []redact.Span
+	text  string
+}
+
+func parse(s redact.RedactableString) text {
+	spans := s.Spans()
+	var b strings.Builder
+	for _, sp := range spans {
+		b.WriteString(sp.Text)
+	}
+	return text{spans: spans, text: b.String()}
+}
+
+// slice returns the redactable string for the stripped text between
+// start and end. A redacted marker cut by the bounds is kept whole.
+func (t text) slice(start, end int) redact.RedactableString {
+	var res []redact.Span
+	pos := 0
+	for _, sp := range t.spans {
+		spStart, spEnd := pos, pos+len(sp.Text)
+		pos = spEnd
+		if spEnd <= start || spStart >= end {
+			continue
+		}
+		if sp.Kind != redact.RedactedSpan {
+			sp.Text = sp.Text[maxInt(start, spStart)-spStart : minInt(end, spEnd)-spStart]
+		}
+		res = append(res, sp)
+	}
+	return build(res)
+}
+
+// build assembles the spans into a redactable string. The texts of the
+// spans come from Spans or are escaped, so they contain no markers.
+func build(spans []redact.Span) redact.RedactableString {
+	s, err := redact.FromSpans(spans)
+	if err != nil {
+		panic("rstrings: " + err.Error() + "; can't happen")
+	}
+	return s
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rstrings
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cockroachdb/redact"
+)
+
+type rs = redact.RedactableString
+
+func TestSplit(t *testing.T) {
+	testCases := []struct {
+		input    rs
+		sep      string
+		expected []rs
+	}{
+		{"", ",", []rs{""}},
+		{"a,b", ",", []rs{"a", "b"}},
+		{"a,‹b,c›,d", ",", []rs{"a", "‹b›", "‹c›", "d"}},
+		{"a‹,›b", ",", []rs{"a", "b"}},
+		{"‹†x,y›", ",", []rs{"‹†x›", "‹†y›"}},
+		{"x ‹×›, y", ", ", []rs{"x ‹×›", "y"}},
+		{"a ‹b", " ", []rs{"a", "‹b›"}},
+		{"a‹é›", "", []rs{"a", "‹é›"}},
+	}
+	for _, tc := range testCases {
+		if actual := Split(tc.input, tc.sep); !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%q: expected %q, got %q", tc.input, tc.expected, actual)
+		}
+	}
+}
+
+func TestFields(t *testing.T) {
+	testCases := []struct {
+		input    rs
+		expected []rs
+	}{
+		{"", nil},
+		{"  ", nil},
+		{" a  b ", []rs{"a", "b"}},
+		{"a ‹b c› d", []rs{"a", "‹b›", "‹c›", "d"}},
+		{"a‹ ›b ‹c›d", []rs{"a", "b", "‹c›d"}},
+	}
+	for _, tc := range testCases {
+		if actual := Fields(tc.input); !reflect.DeepEqual(actual, tc.expected) {
+			t.Errorf("%q: expected %q, got %q", tc.input, tc.expected, actual)
+		}
+	}
+}
+
+func TestTrimSpace(t *testing.T) {
+	testCases := []struct {
+		input, expected rs
+	}{
+		{"", ""},
+		{" a ", "a"},
+		{"‹ a › ", "‹a›"},
+		{" ‹ › a\n", "a"},
+		{"\t‹a ›b ", "‹a ›b"},
+	}
+	for _, tc := range testCases {
+		if actual := TrimSpace(tc.input); actual != tc.expected {
+			t.Errorf("%q: expected %q, got %q", tc.input, tc.expected, actual)
+		}
+	}
+}
+
+func TestLines(t *testing.T) {
+	actual := Lines("a ‹b\nc›\n\nd‹e")
+	expected := []rs{"a ‹b\n›", "‹c›\n", "\n", "d‹e›"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+	if actual := Lines(""); actual != nil {
+		t.Errorf("expected no lines, got %q", actual)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	testCases := []struct {
+		input    rs
+		old, new string
+		n        int
+		expected rs
+	}{
+		{"a-b-c", "-", "+", -1, "a+b+c"},
+		{"a-b-c", "-", "+", 1, "a+b-c"},
+		{"a-‹b-c›-d", "-", "+", -1, "a+‹b-c›+d"},
+		{"a-‹b-c›-d-e", "-", "+", 2, "a+‹b-c›+d-e"},
+		{"ab‹c›", "bc", "x", -1, "ab‹c›"},
+		{"a", "a", "‹x›", -1, "?x?"},
+		{"‹×›", "×", "y", -1, "‹×›"},
+	}
+	for _, tc := range testCases {
+		if actual := Replace(tc.input, tc.old, tc.new, tc.n); actual != tc.expected {
+			t.Errorf("%q: expected %q, got %q", tc.input, tc.expected, actual)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	s := rs("user ‹alice› logged in")
+	for _, tc := range []struct {
+		fn       func(rs, string, View) bool
+		arg      string
+		v        View
+		expected bool
+	}{
+		{HasPrefix, "user alice", Stripped, true},
+		{HasPrefix, "user alice", Redacted, false},
+		{HasPrefix, "user × logged", Redacted, true},
+		{Contains, "alice", Stripped, true},
+		{Contains, "alice", Redacted, false},
+		{Contains, "× logged", Redacted, true},
+		{Contains, "‹alice›", Stripped, false},
+	} {
+		if actual := tc.fn(s, tc.arg, tc.v); actual != tc.expected {
+			t.Errorf("%q %d: expected %v, got %v", tc.arg, tc.v, tc.expected, actual)
+		}
+	}
+}
+
+func TestConcat(t *testing.T) {
+	if actual := Concat("a ‹b", "c› d", "‹e›"); actual != "a ‹b›‹c› d‹e›" {
+		t.Errorf("unexpected result %q", actual)
+	}
+	if actual := Concat(); actual != "" {
+		t.Errorf("unexpected result %q", actual)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+
+	m "github.com/cockroachdb/redact/internal/markers"
+)
+
+// Secret is a value, such as a credential, that is never printed.
+// Unlike unsafe data, which is present in the redactable strings
+// until they are redacted, a Secret is replaced by the redacted
+// marker ‹×› when it is formatted by this package or by package fmt,
+// with the %#v verb, by its String method and when it is marshaled
+// to JSON or text. The value is only available via Reveal.
+//
+// The placeholder is optionally followed by a short fingerprint of
+// the value, see MakeFingerprintedSecret.
+type Secret[T any] struct {
+	// box hides the value behind a pointer, so that the formatters
+	// that walk the unexported struct fields, such as those of package
+	// fmt, print its address instead of the value.
+	box *secretBox[T]
+}
+
+type secretBox[T any] struct {
+	v           T
+	fingerprint bool
+}
+
+// MakeSecret wraps v in a Secret.
+func MakeSecret[T any](v T) Secret[T] {
+	return Secret[T]{&secretBox[T]{v: v}}
+}
+
+// MakeFingerprintedSecret is like MakeSecret, but the placeholder
+// printed for the secret is followed by a fingerprint of the value,
+// so that different secrets can be told apart, e.g. ‹×›#1a2b3c4d. The
+// fingerprint is a truncated HMAC of the value printed with %v, keyed
+// with the salt passed to EnableHashing.
+//
+// The fingerprint is safe text that survives redaction, so it is only
+// printed when hashing is enabled with a non-empty salt: an unsalted
+// hash of a short secret, such as a PIN or a weak password, could be
+// reversed by brute force from the redacted logs. Without a salt, the
+// secret prints as ‹×› like one made by MakeSecret. The salt must be
+// kept as confidential as the secrets themselves.
+func MakeFingerprintedSecret[T any](v T) Secret[T] {
+	return Secret[T]{&secretBox[T]{v: v, fingerprint: true}}
+}
+
+// Reveal returns the value of the secret. The zero Secret holds the
+// zero value of T.
+func (s Secret[T]) Reveal() (v T) {
+	if s.box != nil {
+		v = s.box.v
+	}
+	return v
+}
+
+// SecretValue implements SecretValue.
+func (Secret[T]) SecretValue() {}
+
+// SafeFormat implements SafeFormatter.
+func (s Secret[T]) SafeFormat(p SafePrinter, _ rune) {
+	p.Print(RedactableString(s.placeholder()))
+}
+
+// Format implements fmt.Formatter.
+func (s Secret[T]) Format(f fmt.State, _ rune) {
+	_, _ = f.Write(s.placeholder())
+}
+
+// String implements fmt.Stringer.
+func (s Secret[T]) String() string {
+	return string(s.placeholder())
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Secret[T]) MarshalText() ([]byte, error) {
+	return s.placeholder(), nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Secret[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// placeholder returns the text printed in lieu of the secret.
+func (s Secret[T]) placeholder() []byte {
+	buf := RedactedMarker()
+	if s.box != nil && s.box.fingerprint {
+		if fp, ok := m.AppendFingerprint(append(buf, '#'), []byte(fmt.Sprint(s.box.v))); ok {
+			buf = fp
+		}
+	}
+	return buf
+}
@@ -0,0 +1,120 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testLogin struct {
+	User     string
+	Password Secret[string]
+	key      Secret[[]byte]
+}
+
+func TestSecret(t *testing.T) {
+	s := MakeSecret("hunter2")
+	l := testLogin{User: "alice", Password: s, key: MakeSecret([]byte("k3y"))}
+	jsonOut, err := json.Marshal(l)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		actual string
+		exp    string
+	}{
+		{string(Sprint(s)), `‹×›`},
+		{string(Sprintf("%q %x %#v %10s", s, s, s, s)), `‹×› ‹×› ‹×›          ‹×›`},
+		{string(Sprint(Safe(s))), `‹×›`},
+		{string(Sprint(Unsafe(s))), `‹×›`},
+		{string(Sprint(&s)), `‹×›`},
+		{string(Sprintf("%+v", l)), `{User:‹alice› Password:‹×› key:‹×›}`},
+		{string(Sprint(Safe(l))), `{alice ‹×› ‹×›}`},
+		{string(Sprint(Config(l))), `{User:‹alice› Password:‹×› key:‹×›}`},
+		{fmt.Sprintf("%v %+v %#v", s, s, s), `‹×› ‹×› ‹×›`},
+		{s.String(), `‹×›`},
+		{string(jsonOut), `{"User":"alice","Password":"‹×›"}`},
+		{string(Sprint(Secret[int]{})), `‹×›`},
+	}
+	for i, tc := range testCases {
+		if tc.actual != tc.exp {
+			t.Errorf("%d: expected:\n%s\ngot:\n%s", i, tc.exp, tc.actual)
+		}
+	}
+
+	// The secret does not appear even when package fmt walks the
+	// unexported fields.
+	if actual := fmt.Sprintf("%+v %#v", l, l); strings.Contains(actual, "k3y") ||
+		strings.Contains(actual, "107 51 121") || strings.Contains(actual, "hunter2") {
+		t.Errorf("secret printed: %s", actual)
+	}
+
+	if s.Reveal() != "hunter2" {
+		t.Errorf("unexpected value: %q", s.Reveal())
+	}
+	if (Secret[string]{}).Reveal() != "" {
+		t.Errorf("unexpected zero value")
+	}
+}
+
+func TestFingerprintedSecret(t *testing.T) {
+	s1 := MakeFingerprintedSecret("hunter2")
+	s2 := MakeFingerprintedSecret("hunter3")
+
+	// The fingerprint cannot be produced without a salt.
+	for _, salt := range [][]byte{nil, {}} {
+		func() {
+			if salt != nil {
+				EnableHashing(salt)
+				defer DisableHashing()
+			}
+			if a := Sprint(s1); a != "‹×›" {
+				t.Errorf("expected no fingerprint without a salt, got %s", a)
+			}
+			if a := s1.String(); a != "‹×›" {
+				t.Errorf("expected no fingerprint without a salt, got %s", a)
+			}
+		}()
+	}
+
+	EnableHashing([]byte("salt"))
+	defer DisableHashing()
+	a1, a2 := Sprint(s1), Sprint(s2)
+	if !strings.HasPrefix(string(a1), "‹×›#") || len(a1) != len("‹×›#")+8 {
+		t.Errorf("unexpected fingerprinted secret: %s", a1)
+	}
+	if a1 == a2 {
+		t.Errorf("expected different fingerprints, got %s", a1)
+	}
+	if a1 != Sprint(MakeFingerprintedSecret("hunter2")) {
+		t.Errorf("expected stable fingerprint")
+	}
+	// The fingerprint is safe.
+	if r := a1.Redact(); r != a1 {
+		t.Errorf("expected %s, got %s", a1, r)
+	}
+	if actual := fmt.Sprint(s1); actual != string(a1) {
+		t.Errorf("expected %s, got %s", a1, actual)
+	}
+
+	EnableHashing([]byte("pepper"))
+	if a := Sprint(s1); a == a1 {
+		t.Errorf("expected a different fingerprint with another salt, got %s", a)
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package stdsafe defines the redactable renderings of common types of
+// the Go standard library.
+//
+// Some types are safe as a whole: durations, timestamps, file modes,
+// months, weekdays, reflection kinds and types, error numbers and
+// signals. Others mix safe and sensitive parts and are rendered
+// partially: for example, the operation of an *os.PathError is safe
+// but its path is not, and so is the port of a *net.TCPAddr but not
+// its IP. URLs are rendered by SafeURL with the default options.
+//
+// The IP addresses, of type net.IP or in the network addresses, are
+// unsafe, as they identify users as much as their names do. The
+// loopback and unspecified addresses (127.0.0.1, ::1, 0.0.0.0, ::)
+// are the exception: they do not identify a host, and are safe.
+//
+// The renderings are opt-in. Call Register during initialization, or
+// import the auto subpackage for its side effects:
+//
+//	import _ "github.com/cockroachdb/redact/stdsafe/auto"
+//
+// The types that are not listed here remain unsafe. Timestamps are considered safe; use redact.Unsafe to print a
+// timestamp that is user data, such as a date of birth.
+package stdsafe
+
+import (
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/redact"
+)
+
+// Registry is the set of registration functions through which the
+// renderings are installed.
+type Registry interface {
+	RegisterSafeType(t reflect.Type)
+	RegisterSafeFormatter(t reflect.Type, fn func(v interface{}, p redact.SafePrinter, verb rune))
+}
+
+// Install installs the renderings of this package into r.
+func Install(r Registry) {
+	for _, t := range safeTypes {
+		r.RegisterSafeType(t)
+	}
+	for _, f := range formatters {
+		r.RegisterSafeFormatter(f.t, f.fn)
+	}
+}
+
+// Register installs the renderings of this package into the registry
+// of the redact package. It is idempotent. Like
+// redact.RegisterSafeType, it should be called during initialization.
+func Register() {
+	registerOnce.Do(func() { Install(globalRegistry{}) })
+}
+
+var registerOnce sync.Once
+
+type globalRegistry struct{}
+
+func (globalRegistry) RegisterSafeType(t reflect.Type) { redact.RegisterSafeType(t) }
+
+func (globalRegistry) RegisterSafeFormatter(
+	t reflect.Type, fn func(v interface{}, p redact.SafePrinter, verb rune),
+) {
+	redact.RegisterSafeFormatter(t, fn)
+}
+
+// safeTypes are the types that are safe as a whole.
+var safeTypes = []reflect.Type{
+	reflect.TypeOf(time.Duration(0)),
+	reflect.TypeOf(time.Time{}),
+	reflect.TypeOf(time.Month(0)),
+	reflect.TypeOf(time.Weekday(0)),
+	reflect.TypeOf((*time.Location)(nil)),
+	reflect.TypeOf(fs.FileMode(0)),
+	reflect.TypeOf(reflect.Kind(0)),
+	reflect.TypeOf(reflect.TypeOf(0)),
+}
+
+type formatter struct {
+	t  reflect.Type
+	fn func(v interface{}, p redact.SafePrinter, verb rune)
+}
+
+// formatters are the renderings of the types that are partially safe,
+// or that are errors: since an error library may register a
+// redaction function for all the errors, the safe errors are
+// rendered explicitly.
+var formatters = []formatter{
+	{reflect.TypeOf((*fs.PathError)(nil)), formatPathError},
+	{reflect.TypeOf((*os.LinkError)(nil)), formatLinkError},
+	{reflect.TypeOf((*os.SyscallError)(nil)), formatSyscallError},
+	{reflect.TypeOf((*net.OpError)(nil)), formatOpError},
+	{reflect.TypeOf(net.IP(nil)), formatIP},
+	{reflect.TypeOf((*net.TCPAddr)(nil)), formatTCPAddr},
+	{reflect.TypeOf((*net.UDPAddr)(nil)), formatUDPAddr},
+	{reflect.TypeOf((*strconv.NumError)(nil)), formatNumError},
+	{reflect.TypeOf((*url.URL)(nil)), formatURL},
+}
+
+// formatPathError renders "op ‹path›: err".
+func formatPathError(v interface{}, p redact.SafePrinter, _ rune) {
+	e := v.(*fs.PathError)
+	if e == nil {
+		p.SafeString("<nil>")
+		return
+	}
+	p.Printf("%s %s: %v", redact.SafeString(e.Op), e.Path, e.Err)
+}
+
+// formatLinkError renders "op ‹old› ‹new›: err".
+func formatLinkError(v interface{}, p redact.SafePrinter, _ rune) {
+	e := v.(*os.LinkError)
+	if e == nil {
+		p.SafeString("<nil>")
+		return
+	}
+	p.Printf("%s %s %s: %v", redact.SafeString(e.Op), e.Old, e.New, e.Err)
+}
+
+// formatSyscallError renders "syscall: err".
+func formatSyscallError(v interface{}, p redact.SafePrinter, _ rune) {
+	e := v.(*os.SyscallError)
+	if e == nil {
+		p.SafeString("<nil>")
+		return
+	}
+	p.Printf("%s: %v", redact.SafeString(e.Syscall), e.Err)
+}
+
+// formatOpError renders the error like its Error method, with the
+// addresses rendered by their own formatters.
+func formatOpError(v interface{}, p redact.SafePrinter, _ rune) {
+	e := v.(*net.OpError)
+	if e == nil {
+		p.SafeString("<nil>")
+		return
+	}
+	p.SafeString(redact.SafeString(e.Op))
+	if e.Net != "" {
+		p.Printf(" %s", redact.SafeString(e.Net))
+	}
+	if e.Source != nil {
+		p.Printf(" %v", e.Source)
+	}
+	if e.Addr != nil {
+		if e.Source != nil {
+			p.SafeString("->")
+		} else {
+			p.SafeRune(' ')
+		}
+		p.Print(e.Addr)
+	}
+	p.Printf(": %v", e.Err)
+}
+
+// formatIP renders the address, unsafe unless it is anonymous.
+func formatIP(v interface{}, p redact.SafePrinter, _ rune) {
+	ip := v.(net.IP)
+	if len(ip) == 0 || isAnonymousIP(ip) {
+		p.SafeString(redact.SafeString(ip.String()))
+		return
+	}
+	p.Print(ip.String())
+}
+
+// isAnonymousIP returns true if ip is a loopback or unspecified
+// address, which does not identify a host.
+func isAnonymousIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsUnspecified()
+}
+
+func formatTCPAddr(v interface{}, p redact.SafePrinter, _ rune) {
+	a := v.(*net.TCPAddr)
+	if a == nil {
+		p.SafeString("<nil>")
+		return
+	}
+	formatHostPort(p, a.IP, a.Zone, a.Port)
+}
+
+func formatUDPAddr(v interface{}, p redact.SafePrinter, _ rune) {
+	a := v.(*net.UDPAddr)
+	if a == nil {
+		p.SafeString("<nil>")
+		return
+	}
+	formatHostPort(p, a.IP, a.Zone, a.Port)
+}
+
+// formatHostPort renders "‹host›:port" like net.JoinHostPort. The
+// port is safe, and so is the host if it is an anonymous address
+// without a zone.
+func formatHostPort(p redact.SafePrinter, ip net.IP, zone string, port int) {
+	var host string
+	if len(ip) > 0 {
+		host = ip.String()
+	}
+	if zone != "" {
+		host += "%" + zone
+	}
+	var h interface{} = host
+	if len(ip) > 0 && isAnonymousIP(ip) && zone == "" {
+		h = redact.SafeString(host)
+	}
+	if strings.IndexByte(host, ':') >= 0 {
+		p.Printf("[%v]:%d", h, redact.SafeInt(port))
+	} else {
+		p.Printf("%v:%d", h, redact.SafeInt(port))
+	}
+}
+
+// formatNumError renders "strconv.Func: parsing ‹"num"›: err". The
+// errors of the strconv package are safe.
+func formatNumError(v interface{}, p redact.SafePrinter, _ rune) {
+	e := v.(*strconv.NumError)
+	if e == nil {
+		p.SafeString("<nil>")
+		return
+	}
+	p.Printf("strconv.%s: parsing %q: ", redact.SafeString(e.Func), e.Num)
+	if e.Err == strconv.ErrRange || e.Err == strconv.ErrSyntax {
+		p.SafeString(redact.SafeString(e.Err.Error()))
+	} else {
+		p.Print(e.Err)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build !plan9
+
+package stdsafe
+
+import (
+	"errors"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/redact"
+)
+
+func TestRegister(t *testing.T) {
+	Register()
+	Register()
+
+	tcp := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5432}
+	testCases := []struct {
+		arg interface{}
+		exp redact.RedactableString
+	}{
+		{time.Duration(90 * time.Second), "1m30s"},
+		{time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC), "2026-10-18 12:00:00 +0000 UTC"},
+		{time.March, "March"},
+		{time.Sunday, "Sunday"},
+		{time.UTC, "UTC"},
+		{fs.FileMode(0644), "-rw-r--r--"},
+		{reflect.Struct, "struct"},
+		{reflect.TypeOf(0), "int"},
+		{syscall.ENOENT, "no such file or directory"},
+		{net.IPv4(10, 0, 0, 1), "‹10.0.0.1›"},
+		{net.ParseIP("2001:db8::1"), "‹2001:db8::1›"},
+		{net.IPv4(127, 0, 0, 1), "127.0.0.1"},
+		{net.IPv6loopback, "::1"},
+		{net.IPv4zero, "0.0.0.0"},
+		{net.IP(nil), "<nil>"},
+		{&fs.PathError{Op: "open", Path: "/home/alice", Err: syscall.ENOENT},
+			"open ‹/home/alice›: no such file or directory"},
+		{&os.LinkError{Op: "rename", Old: "a", New: "b", Err: syscall.EEXIST},
+			"rename ‹a› ‹b›: file exists"},
+		{os.NewSyscallError("connect", syscall.ECONNREFUSED), "connect: connection refused"},
+		{tcp, "‹10.0.0.1›:5432"},
+		{&net.UDPAddr{IP: net.ParseIP("fe80::1"), Zone: "eth0", Port: 53}, "[‹fe80::1%eth0›]:53"},
+		{&net.TCPAddr{IP: net.IPv6loopback, Port: 8080}, "[::1]:8080"},
+		{&net.TCPAddr{Port: 8080}, ":8080"},
+		{&net.OpError{Op: "dial", Net: "tcp", Addr: tcp, Err: syscall.ECONNREFUSED},
+			"dial tcp ‹10.0.0.1›:5432: connection refused"},
+		{&net.OpError{Op: "read", Net: "tcp", Source: tcp, Addr: tcp, Err: errors.New("boom")},
+			"read tcp ‹10.0.0.1›:5432->‹10.0.0.1›:5432: ‹boom›"},
+		{&strconv.NumError{Func: "Atoi", Num: "x1", Err: strconv.ErrSyntax},
+			`strconv.Atoi: parsing ‹"x1"›: invalid syntax`},
+		{&url.URL{Scheme: "https", Host: "example.com", RawQuery: "q=x"}, "https://example.com?q=‹x›"},
+		{(*fs.PathError)(nil), "<nil>"},
+		// Unsafe overrides the registration.
+		{redact.Unsafe(time.March), "‹March›"},
+	}
+	for _, tc := range testCases {
+		if actual := redact.Sprint(tc.arg); actual != tc.exp {
+			t.Errorf("%T: expected %q, got %q", tc.arg, tc.exp, actual)
+		}
+		// The renderings agree with the standard library once the
+		// markers are removed.
+		if err, ok := tc.arg.(error); ok && tc.exp != "<nil>" {
+			if actual := redact.Sprint(err).StripMarkers(); actual != err.Error() {
+				t.Errorf("%T: expected %q, got %q", tc.arg, err.Error(), actual)
+			}
+		}
+	}
+}
+
+type testRegistry struct {
+	types      int
+	formatters int
+}
+
+func (r *testRegistry) RegisterSafeType(reflect.Type) { r.types++ }
+
+func (r *testRegistry) RegisterSafeFormatter(
+	reflect.Type, func(v interface{}, p redact.SafePrinter, verb rune),
+) {
+	r.formatters++
+}
+
+func TestInstall(t *testing.T) {
+	var r testRegistry
+	Install(&r)
+	if r.types != len(safeTypes) || r.formatters != len(formatters) {
+		t.Errorf("unexpected registrations: %+v", r)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build !plan9
+
+package stdsafe
+
+import (
+	"reflect"
+	"syscall"
+
+	"github.com/cockroachdb/redact"
+)
+
+func init() {
+	safeTypes = append(safeTypes, reflect.TypeOf(syscall.Signal(0)))
+	formatters = append(formatters, formatter{reflect.TypeOf(syscall.Errno(0)), formatErrno})
+}
+
+// formatErrno renders the description of the error number, which is
+// safe.
+func formatErrno(v interface{}, p redact.SafePrinter, _ rune) {
+	p.SafeString(redact.SafeString(v.(syscall.Errno).Error()))
+}
@@ -0,0 +1,198 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package stdsafe
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/cockroachdb/redact"
+)
+
+// URLPart is the rendering of a part of a URL.
+type URLPart int
+
+const (
+	// URLUnsafe marks the part as unsafe, or hashes it with
+	// URLOptions.HashValues.
+	URLUnsafe URLPart = iota
+	// URLSafe prints the part safely.
+	URLSafe
+	// URLHidden replaces the part by the redacted marker, ‹×›, so that
+	// it does not appear even before redaction.
+	URLHidden
+)
+
+// URLOptions configures the rendering of SafeURL.
+type URLOptions struct {
+	// HashValues hashes the unsafe parts instead of marking them for
+	// redaction. See redact.HashString.
+	HashValues bool
+	// SafeParams are the query parameters whose values are safe.
+	SafeParams []string
+	// HiddenParams are the query parameters whose values are hidden,
+	// e.g. access tokens.
+	HiddenParams []string
+	// Segment, if set, selects the rendering of the i-th segment of
+	// the path, counting from 0. By default the path is safe.
+	Segment func(i int, segment string) URLPart
+}
+
+// SafeURL returns a SafeFormatter rendering u the way u.String() does,
+// except that:
+//
+//   - the scheme, the host and the path are safe;
+//   - the user name is unsafe and the password is hidden;
+//   - the query parameter names are safe and their values unsafe;
+//   - the fragment and the opaque data are unsafe.
+//
+// The options can make more parts safe or hidden.
+func SafeURL(u *url.URL, opts URLOptions) redact.SafeFormatter {
+	return urlFormatter{u: u, opts: opts}
+}
+
+type urlFormatter struct {
+	u    *url.URL
+	opts URLOptions
+}
+
+// SafeFormat implements redact.SafeFormatter.
+func (f urlFormatter) SafeFormat(p redact.SafePrinter, _ rune) {
+	u := f.u
+	if u == nil {
+		p.SafeString("<nil>")
+		return
+	}
+	if u.Scheme != "" {
+		p.SafeString(redact.SafeString(u.Scheme))
+		p.SafeRune(':')
+	}
+	if u.Opaque != "" {
+		f.part(p, u.Opaque, URLUnsafe)
+	} else {
+		if u.Scheme != "" || u.Host != "" || u.User != nil {
+			if u.OmitHost && u.Host == "" && u.User == nil {
+				// Omitted, like in u.String().
+			} else {
+				if u.Host != "" || u.Path != "" || u.User != nil {
+					p.SafeString("//")
+				}
+				if ui := u.User; ui != nil {
+					f.part(p, url.User(ui.Username()).String(), URLUnsafe)
+					if _, ok := ui.Password(); ok {
+						p.SafeRune(':')
+						f.part(p, "", URLHidden)
+					}
+					p.SafeRune('@')
+				}
+				if h := u.Host; h != "" {
+					// Let net/url escape the host.
+					h = strings.TrimPrefix((&url.URL{Host: h}).String(), "//")
+					p.SafeString(redact.SafeString(h))
+				}
+			}
+		}
+		path := u.EscapedPath()
+		if path != "" && path[0] != '/' && u.Host != "" {
+			p.SafeRune('/')
+		}
+		f.path(p, path)
+	}
+	if u.ForceQuery || u.RawQuery != "" {
+		p.SafeRune('?')
+		f.query(p, u.RawQuery)
+	}
+	if u.Fragment != "" {
+		p.SafeRune('#')
+		f.part(p, u.EscapedFragment(), URLUnsafe)
+	}
+}
+
+// path renders the escaped path, segment by segment.
+func (f urlFormatter) path(p redact.SafePrinter, path string) {
+	if f.opts.Segment == nil {
+		p.SafeString(redact.SafeString(path))
+		return
+	}
+	i := 0
+	for j, seg := range strings.Split(path, "/") {
+		if j > 0 {
+			p.SafeRune('/')
+		}
+		if seg == "" {
+			continue
+		}
+		unescaped, err := url.PathUnescape(seg)
+		if err != nil {
+			unescaped = seg
+		}
+		f.part(p, seg, f.opts.Segment(i, unescaped))
+		i++
+	}
+}
+
+// query renders the raw query, parameter by parameter.
+func (f urlFormatter) query(p redact.SafePrinter, query string) {
+	for j, param := range strings.Split(query, "&") {
+		if j > 0 {
+			p.SafeRune('&')
+		}
+		key, value, hasValue := strings.Cut(param, "=")
+		p.SafeString(redact.SafeString(key))
+		if !hasValue {
+			continue
+		}
+		p.SafeRune('=')
+		name, err := url.QueryUnescape(key)
+		if err != nil {
+			name = key
+		}
+		f.part(p, value, f.param(name))
+	}
+}
+
+// param returns the rendering of the value of the query parameter.
+func (f urlFormatter) param(name string) URLPart {
+	for _, n := range f.opts.HiddenParams {
+		if n == name {
+			return URLHidden
+		}
+	}
+	for _, n := range f.opts.SafeParams {
+		if n == name {
+			return URLSafe
+		}
+	}
+	return URLUnsafe
+}
+
+// part renders s according to the rendering r.
+func (f urlFormatter) part(p redact.SafePrinter, s string, r URLPart) {
+	switch {
+	case r == URLSafe:
+		p.SafeString(redact.SafeString(s))
+	case r == URLHidden:
+		p.Print(redact.RedactableString(redact.RedactedMarker()))
+	case s == "":
+	case f.opts.HashValues:
+		p.Print(redact.HashString(s))
+	default:
+		p.Print(s)
+	}
+}
+
+func formatURL(v interface{}, p redact.SafePrinter, verb rune) {
+	SafeURL(v.(*url.URL), URLOptions{}).SafeFormat(p, verb)
+}
@@ -0,0 +1,30 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tabwriter
+
+import m "github.com/cockroachdb/redact/internal/markers"
+
+// redactCell redacts the text of the current cell, for the Redact
+// flag. The width of the cell is adjusted by the difference of visible
+// width between the original and redacted text.
+func (b *Writer) redactCell() {
+	start := len(b.buf) - b.cell.size
+	text := b.buf[start:]
+	redacted := m.RedactableBytes(text).Repair().Redact()
+	b.cell.width += m.VisibleWidth(redacted) - m.VisibleWidth(text)
+	b.buf = append(b.buf[:start], redacted...)
+	b.cell.size = len(redacted)
+	b.pos = len(b.buf)
+}
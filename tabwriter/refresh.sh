@@ -0,0 +1,9 @@
+#!/usr/bin/env bash
+#
+# This file re-generates the sources in this directory from the Go
+# standard library.
+#
+set -euxo pipefail
+
+cp $GOROOT/src/text/tabwriter/tabwriter.go tabwriter.go
+patch -p0 <tabwriter.go.diff
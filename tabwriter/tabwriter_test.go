@@ -0,0 +1,130 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tabwriter_test
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	stdtabwriter "text/tabwriter"
+
+	"github.com/cockroachdb/redact"
+	"github.com/cockroachdb/redact/tabwriter"
+)
+
+func format(t *testing.T, flags uint, input string) string {
+	t.Helper()
+	var buf strings.Builder
+	w := tabwriter.NewWriter(&buf, 0, 8, 1, '.', flags)
+	// Write byte by byte to exercise the markers split across writes.
+	for i := 0; i < len(input); i++ {
+		if _, err := w.Write([]byte{input[i]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestWriter(t *testing.T) {
+	testCases := []struct {
+		name     string
+		flags    uint
+		input    string
+		expected string
+	}{
+		{
+			name:     "markers have no width",
+			input:    "user\tid\n‹alice›\t1\nbob\t‹2›\n",
+			expected: "user..id\n‹alice›.1\nbob...‹2›\n",
+		},
+		{
+			name:     "hash prefix",
+			input:    "‹†alice›\tx\nbob\ty\n",
+			expected: "‹†alice›.x\nbob...y\n",
+		},
+		{
+			name:     "right alignment",
+			flags:    tabwriter.AlignRight,
+			input:    "‹alice›\t1\nbob\t2\n",
+			expected: ".‹alice›1\n...bob2\n",
+		},
+		{
+			name:     "redacted",
+			flags:    tabwriter.Redact,
+			input:    "user\tid\n‹alice›\t1\nbob\t‹22›\n",
+			expected: "user.id\n‹×›....1\nbob..‹×›\n",
+		},
+		{
+			name:     "redacted region spanning cells",
+			flags:    tabwriter.Redact,
+			input:    "a ‹b\tc› d\te\n",
+			expected: "a ‹×›.‹×› d.e\n",
+		},
+		{
+			name:     "redacted unclosed region",
+			flags:    tabwriter.Redact,
+			input:    "abc\t‹secret",
+			expected: "abc.‹×›",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := format(t, tc.flags, tc.input); actual != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, actual)
+			}
+		})
+	}
+}
+
+// TestStripMarkers checks that the output, once the markers are
+// stripped, is that of text/tabwriter on the input with the markers
+// stripped.
+func TestStripMarkers(t *testing.T) {
+	inputs := []string{
+		"a\tb\tc\n‹aaaa›\t‹b›\tc\n",
+		"‹x›\t\t‹y›\n\t‹zzz›\t\n",
+		"<b>‹x›</b>\t&amp;\n‹é›\t\xff\t‹y›\xff\n",
+	}
+	for _, flags := range []uint{0, tabwriter.AlignRight, tabwriter.FilterHTML | tabwriter.StripEscape} {
+		for _, input := range inputs {
+			var expected strings.Builder
+			w := stdtabwriter.NewWriter(&expected, 0, 8, 1, '.', flags)
+			fmt.Fprint(w, redact.RedactableString(input).StripMarkers())
+			_ = w.Flush()
+
+			actual := redact.RedactableString(format(t, flags, input)).StripMarkers()
+			if actual != expected.String() {
+				t.Errorf("%q: expected:\n%s\ngot:\n%s", input, expected.String(), actual)
+			}
+		}
+	}
+}
+
+func Example() {
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, ' ', tabwriter.Redact)
+	redact.Fprintf(w, "%s\t%s\t%s\n", redact.SafeString("USER"), redact.SafeString("ROLE"), redact.SafeString("ID"))
+	redact.Fprintf(w, "%s\t%s\t%d\n", "alice", redact.SafeString("admin"), 1)
+	redact.Fprintf(w, "%s\t%s\t%d\n", "bob", redact.SafeString("viewer"), 22)
+	_ = w.Flush()
+
+	// Output:
+	// USER ROLE   ID
+	// ‹×›    admin  ‹×›
+	// ‹×›    viewer ‹×›
+}
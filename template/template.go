@@ -0,0 +1,391 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package template implements data-driven templates producing
+// redactable strings. It has the same interface as text/template.
+//
+// The literal text of the templates is safe. The values printed by
+// the actions are printed by redact.Sprint: they are safe if they
+// implement SafeValue or SafeFormatter, unsafe otherwise. As in
+// text/template, the missing values print <no value>. The following
+// functions are predefined in addition to those of text/template:
+//
+//	safe
+//		Returns its argument marked as safe, see redact.Safe.
+//	unsafe
+//		Returns its argument marked as unsafe, see redact.Unsafe.
+//	hash
+//		Returns its argument marked for hashing, see redact.HashValue.
+//
+// For example, {{.Name | safe}} prints the name as safe data.
+package template
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/cockroachdb/redact"
+)
+
+// FuncMap is the type of the map defining the mapping from names to
+// functions. See text/template.FuncMap.
+type FuncMap = template.FuncMap
+
+// Template is the representation of a parsed template.
+type Template struct {
+	text *template.Template
+	*nameSpace
+}
+
+// nameSpace is shared by the associated templates.
+type nameSpace struct {
+	mu sync.Mutex
+	// rewritten are the parse trees rewritten for redaction.
+	rewritten map[*parse.Tree]bool
+}
+
+// printFn is the function appended to the pipelines of the actions to
+// print their value.
+const printFn = "_redact_print"
+
+var builtins = FuncMap{
+	"safe":   redact.Safe,
+	"unsafe": redact.Unsafe,
+	"hash":   hash,
+	printFn:  printValue,
+}
+
+func hash(v interface{}) redact.HashString {
+	return redact.HashString(redact.Sprint(v).StripMarkers())
+}
+
+// noValue is printed by text/template for the missing values.
+const noValue = "<no value>"
+
+// printValue prints the value of an action. The missing values, e.g.
+// the missing keys of a map, reach it as nil, and are printed as
+// <no value> like text/template does. text/template also passes the
+// nil interface{} values this way, and prints them the same.
+func printValue(v interface{}) redact.RedactableString {
+	if v == nil {
+		return noValue
+	}
+	return redact.Sprint(v)
+}
+
+// New allocates a new template with the given name.
+func New(name string) *Template {
+	return &Template{
+		text:      template.New(name).Funcs(builtins),
+		nameSpace: &nameSpace{rewritten: map[*parse.Tree]bool{}},
+	}
+}
+
+// Must is a helper that wraps a call to a function returning
+// (*Template, error) and panics if the error is non-nil.
+func Must(t *Template, err error) *Template {
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// wrap returns the template associated with t for text.
+func (t *Template) wrap(text *template.Template) *Template {
+	if text == nil {
+		return nil
+	}
+	return &Template{text: text, nameSpace: t.nameSpace}
+}
+
+// Name returns the name of the template.
+func (t *Template) Name() string { return t.text.Name() }
+
+// New allocates a new template associated with t. See
+// text/template.Template.New.
+func (t *Template) New(name string) *Template { return t.wrap(t.text.New(name)) }
+
+// Lookup returns the template with the given name that is associated
+// with t, or nil if there is no such template.
+func (t *Template) Lookup(name string) *Template { return t.wrap(t.text.Lookup(name)) }
+
+// Templates returns a slice of the templates associated with t,
+// including t itself if it is defined.
+func (t *Template) Templates() []*Template {
+	ts := t.text.Templates()
+	res := make([]*Template, len(ts))
+	for i, text := range ts {
+		res[i] = t.wrap(text)
+	}
+	return res
+}
+
+// DefinedTemplates returns a string listing the defined templates.
+// See text/template.Template.DefinedTemplates.
+func (t *Template) DefinedTemplates() string { return t.text.DefinedTemplates() }
+
+// Delims sets the action delimiters for the subsequent calls to
+// Parse, ParseFiles, ParseGlob or ParseFS.
+func (t *Template) Delims(left, right string) *Template {
+	t.text.Delims(left, right)
+	return t
+}
+
+// Funcs adds the elements of the argument map to the template's
+// function map. See text/template.Template.Funcs.
+func (t *Template) Funcs(funcMap FuncMap) *Template {
+	t.text.Funcs(funcMap)
+	return t
+}
+
+// Option sets options for the template. See
+// text/template.Template.Option.
+func (t *Template) Option(opt ...string) *Template {
+	t.text.Option(opt...)
+	return t
+}
+
+// Clone returns a duplicate of the template, including all associated
+// templates. See text/template.Template.Clone.
+func (t *Template) Clone() (*Template, error) {
+	text, err := t.text.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return t.wrap(text), nil
+}
+
+// Parse parses text as a template body for t. See
+// text/template.Template.Parse.
+func (t *Template) Parse(text string) (*Template, error) {
+	if _, err := t.text.Parse(text); err != nil {
+		return nil, err
+	}
+	t.rewrite()
+	return t, nil
+}
+
+// AddParseTree associates a copy of the argument parse tree with the
+// template t, giving it the specified name. The tree itself is not
+// modified, so it can be shared with other templates. See
+// text/template.Template.AddParseTree.
+func (t *Template) AddParseTree(name string, tree *parse.Tree) (*Template, error) {
+	text, err := t.text.AddParseTree(name, tree.Copy())
+	if err != nil {
+		return nil, err
+	}
+	t.rewrite()
+	return t.wrap(text), nil
+}
+
+// Execute applies the template to data, and writes the output to wr,
+// with redaction markers around the unsafe data.
+func (t *Template) Execute(wr io.Writer, data interface{}) error {
+	return t.text.Execute(wr, data)
+}
+
+// ExecuteTemplate applies the template associated with t that has the
+// given name to data, and writes the output to wr.
+func (t *Template) ExecuteTemplate(wr io.Writer, name string, data interface{}) error {
+	return t.text.ExecuteTemplate(wr, name, data)
+}
+
+// ExecuteRedactable applies the template to data, and returns the
+// output.
+func (t *Template) ExecuteRedactable(data interface{}) (redact.RedactableString, error) {
+	var b strings.Builder
+	err := t.Execute(&b, data)
+	return redact.RedactableString(b.String()), err
+}
+
+// ExecuteTemplateRedactable applies the template associated with t
+// that has the given name to data, and returns the output.
+func (t *Template) ExecuteTemplateRedactable(
+	name string, data interface{},
+) (redact.RedactableString, error) {
+	var b strings.Builder
+	err := t.ExecuteTemplate(&b, name, data)
+	return redact.RedactableString(b.String()), err
+}
+
+// rewrite rewrites the parse trees of the templates associated with t
+// that are not rewritten yet: the markers are escaped in the text
+// nodes, and the values of the actions are printed by printFn.
+func (t *Template) rewrite() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, text := range t.text.Templates() {
+		if text.Tree == nil || t.rewritten[text.Tree] {
+			continue
+		}
+		t.rewritten[text.Tree] = true
+		rewriteNode(text.Tree.Root)
+	}
+}
+
+func rewriteNode(node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			rewriteNode(c)
+		}
+	case *parse.TextNode:
+		n.Text = redact.EscapeMarkers(n.Text)
+	case *parse.ActionNode:
+		// The actions declaring variables print nothing.
+		if len(n.Pipe.Decl) == 0 {
+			n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+				NodeType: parse.NodeCommand,
+				Pos:      n.Pos,
+				Args:     []parse.Node{parse.NewIdentifier(printFn).SetPos(n.Pos)},
+			})
+		}
+	case *parse.IfNode:
+		rewriteNode(n.List)
+		rewriteNode(n.ElseList)
+	case *parse.RangeNode:
+		rewriteNode(n.List)
+		rewriteNode(n.ElseList)
+	case *parse.WithNode:
+		rewriteNode(n.List)
+		rewriteNode(n.ElseList)
+	}
+}
+
+// ParseFiles creates a new Template and parses the template
+// definitions from the named files. See text/template.ParseFiles.
+func ParseFiles(filenames ...string) (*Template, error) {
+	return parseFiles(nil, readFileOS, filenames...)
+}
+
+// ParseFiles parses the named files and associates the resulting
+// templates with t. See text/template.Template.ParseFiles.
+func (t *Template) ParseFiles(filenames ...string) (*Template, error) {
+	return parseFiles(t, readFileOS, filenames...)
+}
+
+// ParseGlob creates a new Template and parses the template definitions
+// from the files identified by the pattern. See
+// text/template.ParseGlob.
+func ParseGlob(pattern string) (*Template, error) {
+	return parseGlob(nil, pattern)
+}
+
+// ParseGlob parses the template definitions in the files identified
+// by the pattern and associates the resulting templates with t. See
+// text/template.Template.ParseGlob.
+func (t *Template) ParseGlob(pattern string) (*Template, error) {
+	return parseGlob(t, pattern)
+}
+
+// ParseFS is like ParseFiles or ParseGlob but reads from the file
+// system fsys instead of the host operating system's file system.
+func ParseFS(fsys fs.FS, patterns ...string) (*Template, error) {
+	return parseFS(nil, fsys, patterns)
+}
+
+// ParseFS is like ParseFiles or ParseGlob but reads from the file
+// system fsys instead of the host operating system's file system.
+func (t *Template) ParseFS(fsys fs.FS, patterns ...string) (*Template, error) {
+	return parseFS(t, fsys, patterns)
+}
+
+// parseFiles is the helper for the method and function. If the
+// argument template is nil, it is created from the first file. This
+// mirrors text/template, which cannot be used directly because the
+// functions must be defined before the files are parsed.
+func parseFiles(
+	t *Template, readFile func(string) (string, []byte, error), filenames ...string,
+) (*Template, error) {
+	if len(filenames) == 0 {
+		// Not really a problem, but be consistent.
+		return nil, fmt.Errorf("template: no files named in call to ParseFiles")
+	}
+	for _, filename := range filenames {
+		name, b, err := readFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		// The first template becomes the return value if not already
+		// defined, and we use that one for subsequent New calls to
+		// associate all the templates together.
+		if t == nil {
+			t = New(name)
+		}
+		var tmpl *Template
+		if name == t.Name() {
+			tmpl = t
+		} else {
+			tmpl = t.New(name)
+		}
+		if _, err := tmpl.Parse(string(b)); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// parseGlob is the implementation of the function and method
+// ParseGlob.
+func parseGlob(t *Template, pattern string) (*Template, error) {
+	filenames, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(filenames) == 0 {
+		return nil, fmt.Errorf("template: pattern matches no files: %#q", pattern)
+	}
+	return parseFiles(t, readFileOS, filenames...)
+}
+
+// parseFS is the implementation of the function and method ParseFS.
+func parseFS(t *Template, fsys fs.FS, patterns []string) (*Template, error) {
+	var filenames []string
+	for _, pattern := range patterns {
+		list, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(list) == 0 {
+			return nil, fmt.Errorf("template: pattern matches no files: %#q", pattern)
+		}
+		filenames = append(filenames, list...)
+	}
+	return parseFiles(t, readFileFS(fsys), filenames...)
+}
+
+func readFileOS(file string) (name string, b []byte, err error) {
+	name = filepath.Base(file)
+	b, err = os.ReadFile(file)
+	return
+}
+
+func readFileFS(fsys fs.FS) func(string) (string, []byte, error) {
+	return func(file string) (name string, b []byte, err error) {
+		name = path.Base(file)
+		b, err = fs.ReadFile(fsys, file)
+		return
+	}
+}
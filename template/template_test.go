@@ -0,0 +1,143 @@
+// Copyright 2026 The Cockroach Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or
+// implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package template
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	texttemplate "text/template"
+
+	"github.com/cockroachdb/redact"
+)
+
+type testNode struct {
+	ID   redact.SafeInt
+	Addr string
+	Tags []string
+}
+
+func TestExecute(t *testing.T) {
+	data := map[string]interface{}{
+		"Nodes": []testNode{
+			{ID: 1, Addr: "10.0.0.1", Tags: []string{"a", "b"}},
+			{ID: 2, Addr: "10.0.0.2"},
+		},
+		"User":    "alice",
+		"Cluster": "prod",
+		"Msg":     redact.Sprintf("%s connected", "bob"),
+		"Nil":     nil,
+	}
+	testCases := []struct {
+		tmpl string
+		exp  redact.RedactableString
+	}{
+		{`user {{.User}}`, `user ‹alice›`},
+		{`{{range .Nodes}}n{{.ID}} at {{.Addr}}{{range .Tags}} {{.}}{{end}}; {{end}}`,
+			`n1 at ‹10.0.0.1› ‹a› ‹b›; n2 at ‹10.0.0.2›; `},
+		{`{{.Cluster | safe}} {{unsafe 42}} {{hash .User}}`, `prod ‹42› ‹†alice›`},
+		{`{{.Msg}}`, `‹bob› connected`},
+		{`{{$u := .User}}{{if $u}}{{$u}}{{else}}none{{end}}`, `‹alice›`},
+		{`{{with .Missing}}{{.}}{{else}}no {{"value"}}{{end}}`, `no ‹value›`},
+		{`{{printf "%s@%s" .User .Cluster}}`, `‹alice@prod›`},
+		{`{{define "n"}}<{{.}}>{{end}}{{template "n" .User}}`, `<‹alice›>`},
+		{`‹literal› {{len .Nodes}}`, `?literal? ‹2›`},
+		// The bodies of range and with are redacted in the nested
+		// templates, and around them.
+		{`{{define "node"}}{{with .Addr}}at {{.}}{{end}}{{range .Tags}} {{.}}{{end}}{{end}}` +
+			`{{range .Nodes}}[{{template "node" .}}]{{end}}` +
+			`{{with .User}} by {{template "user" .}} {{.}}{{end}}` +
+			`{{define "user"}}{{with $u := .}}<{{$u}}>{{end}}{{end}}`,
+			`[at ‹10.0.0.1› ‹a› ‹b›][at ‹10.0.0.2›] by <‹alice›> ‹alice›`},
+		// Like text/template.
+		{`{{.Missing}} {{.Nil}}`, `<no value> <no value>`},
+	}
+	for _, tc := range testCases {
+		tmpl := Must(New("test").Parse(tc.tmpl))
+		actual, err := tmpl.ExecuteRedactable(data)
+		if err != nil {
+			t.Errorf("%s: %v", tc.tmpl, err)
+			continue
+		}
+		if actual != tc.exp {
+			t.Errorf("%s: expected:\n%s\ngot:\n%s", tc.tmpl, tc.exp, actual)
+		}
+	}
+}
+
+func TestParseFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.tmpl": {Data: []byte(`main: {{template "sub.tmpl" .}}`)},
+		"sub.tmpl":  {Data: []byte(`sub {{.}}`)},
+	}
+	tmpl, err := ParseFS(fsys, "*.tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.Name() != "main.tmpl" {
+		t.Errorf("unexpected name: %s", tmpl.Name())
+	}
+	if names := tmpl.DefinedTemplates(); !strings.Contains(names, `"sub.tmpl"`) {
+		t.Errorf("unexpected templates: %s", names)
+	}
+
+	// Clones share the parsed trees, which are not rewritten twice.
+	clone := Must(tmpl.Clone())
+	Must(clone.New("other").Parse(`other`))
+
+	var b strings.Builder
+	if err := clone.Execute(&b, "x"); err != nil {
+		t.Fatal(err)
+	}
+	if exp := `main: sub ‹x›`; b.String() != exp {
+		t.Errorf("expected %s, got %s", exp, b.String())
+	}
+	actual, err := tmpl.ExecuteTemplateRedactable("sub.tmpl", "y")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := redact.RedactableString(`sub ‹y›`); actual != exp {
+		t.Errorf("expected %s, got %s", exp, actual)
+	}
+	if tmpl.Lookup("other") != nil {
+		t.Errorf("unexpected template in the original")
+	}
+}
+
+func TestAddParseTree(t *testing.T) {
+	text := texttemplate.Must(texttemplate.New("text").Parse(`‹a› {{.}}`))
+	tmpl := New("root")
+	// Adding the tree twice does not escape it twice.
+	for _, name := range []string{"x", "y"} {
+		if _, err := tmpl.AddParseTree(name, text.Tree); err != nil {
+			t.Fatal(err)
+		}
+		actual, err := tmpl.ExecuteTemplateRedactable(name, "b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp := redact.RedactableString(`?a? ‹b›`); actual != exp {
+			t.Errorf("%s: expected %s, got %s", name, exp, actual)
+		}
+	}
+	// The text/template sharing the tree is unchanged.
+	var b strings.Builder
+	if err := text.Execute(&b, "b"); err != nil {
+		t.Fatal(err)
+	}
+	if exp := `‹a› b`; b.String() != exp {
+		t.Errorf("expected %s, got %s", exp, b.String())
+	}
+}
@@ -53,6 +53,43 @@ func SortStrings(s []RedactableString) {
 	sort.Sort(sortableSlice(s))
 }
 
+// SortStringsStripped sorts the provided slice of redactable strings
+// by their text with the markers stripped, so that the strings that
+// differ only by their markers are ordered alike. The sort is stable.
+func SortStringsStripped(s []RedactableString) {
+	sortStringsBy(s, RedactableString.StripMarkers)
+}
+
+// SortStringsRedacted sorts the provided slice of redactable strings
+// by their redacted text with the markers stripped, i.e. in the order
+// that would be observed after redaction. The sort is stable.
+func SortStringsRedacted(s []RedactableString) {
+	sortStringsBy(s, func(s RedactableString) string { return s.Redact().StripMarkers() })
+}
+
+func sortStringsBy(s []RedactableString, key func(RedactableString) string) {
+	keys := make([]string, len(s))
+	for i := range s {
+		keys[i] = key(s[i])
+	}
+	sort.Stable(keyedSlice{s, keys})
+}
+
+// keyedSlice attaches the methods of sort.Interface to
+// []RedactableString, sorting by the associated keys in increasing
+// order.
+type keyedSlice struct {
+	s    []RedactableString
+	keys []string
+}
+
+func (p keyedSlice) Len() int           { return len(p.s) }
+func (p keyedSlice) Less(i, j int) bool { return p.keys[i] < p.keys[j] }
+func (p keyedSlice) Swap(i, j int) {
+	p.s[i], p.s[j] = p.s[j], p.s[i]
+	p.keys[i], p.keys[j] = p.keys[j], p.keys[i]
+}
+
 // sortableSlice attaches the methods of sort.Interface to
 // []RedactableString, sorting in increasing order.
 type sortableSlice []RedactableString
@@ -30,6 +30,36 @@ func TestSort(t *testing.T) {
 	}
 }
 
+func TestSortViews(t *testing.T) {
+	v := []RedactableString{"‹b›", "a", "‹c› z", "c y", "‹a›"}
+	SortStringsStripped(v)
+	exp := []RedactableString{"a", "‹a›", "‹b›", "c y", "‹c› z"}
+	if !reflect.DeepEqual(v, exp) {
+		t.Errorf("expected %+v, got %+v", exp, v)
+	}
+	SortStringsRedacted(v)
+	exp = []RedactableString{"a", "c y", "‹a›", "‹b›", "‹c› z"}
+	if !reflect.DeepEqual(v, exp) {
+		t.Errorf("expected %+v, got %+v", exp, v)
+	}
+}
+
+func TestCanonicalPrint(t *testing.T) {
+	// The output of the printing functions is in normal form.
+	for _, s := range []RedactableString{
+		Sprintf("%s %s", "a\nb", "c"),
+		Sprint("a", "b", Safe("c"), "d\n", HashString("e")),
+		Sprintf("%v", []string{"x", "y"}),
+	} {
+		if c := s.Canonicalize(); c != s {
+			t.Errorf("expected %q, got %q", s, c)
+		}
+	}
+	if !RedactableString("‹a›‹b›").Equal(Sprint("ab")) {
+		t.Errorf("expected equal strings")
+	}
+}
+
 func TestJoin(t *testing.T) {
 	v := []RedactableString{"c", "a", "b"}
 	exp := RedactableString("c, a, b")